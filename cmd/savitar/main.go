@@ -12,8 +12,8 @@ func main() {
     cm := cluster.NewClusterManager(3)
 
     // Add sample vectors
-    vector1 := vector.Vector{ID: "vec1", Data: []float64{1.0, 2.0, 3.0}, Meta: map[string]string{"type": "image"}}
-    vector2 := vector.Vector{ID: "vec2", Data: []float64{4.0, 5.0, 6.0}, Meta: map[string]string{"type": "text"}}
+    vector1 := vector.Vector{ID: "vec1", Data: []float64{1.0, 2.0, 3.0}, Meta: map[string]any{"type": "image"}}
+    vector2 := vector.Vector{ID: "vec2", Data: []float64{4.0, 5.0, 6.0}, Meta: map[string]any{"type": "text"}}
 
     // Add vectors to the cluster
     if err := cm.AddVector(vector1); err != nil {
@@ -0,0 +1,58 @@
+// Command savitard starts a Savitar cluster and serves it over both gRPC
+// and HTTP/JSON, turning the in-process demos in cmd/savitar and main.go
+// into a network-accessible database.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+
+	"google.golang.org/grpc"
+
+	"github.com/im-naren/savitar/pkg/api"
+	"github.com/im-naren/savitar/pkg/api/pb"
+	"github.com/im-naren/savitar/pkg/cluster"
+)
+
+func main() {
+	nodeCount := flag.Int("nodes", 3, "number of nodes in the cluster")
+	grpcAddr := flag.String("grpc-addr", ":9090", "address to serve the gRPC API on")
+	httpAddr := flag.String("http-addr", ":8080", "address to serve the HTTP/JSON gateway on")
+	shardRuleFile := flag.String("shard-rule-file", "", "path to a JavaScript file defining a shardKey(id, meta) function for custom placement (see pkg/shardrule); falls back to consistent hashing if unset")
+	flag.Parse()
+
+	cfg := cluster.Config{NodeCount: *nodeCount}
+	if *shardRuleFile != "" {
+		source, err := os.ReadFile(*shardRuleFile)
+		if err != nil {
+			log.Fatalf("reading -shard-rule-file: %v", err)
+		}
+		cfg.ShardRule = string(source)
+	}
+	cm, err := cluster.NewClusterManagerFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("starting cluster: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", *grpcAddr)
+	if err != nil {
+		log.Fatalf("listen %s: %v", *grpcAddr, err)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterSavitarServer(grpcServer, api.NewServer(cm))
+
+	go func() {
+		log.Printf("savitard: gRPC serving on %s", *grpcAddr)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("grpc serve: %v", err)
+		}
+	}()
+
+	log.Printf("savitard: HTTP gateway serving on %s", *httpAddr)
+	if err := http.ListenAndServe(*httpAddr, api.NewGateway(cm).Handler()); err != nil {
+		log.Fatalf("http serve: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/im-naren/savitar/pkg/cluster"
+	"github.com/im-naren/savitar/pkg/filter"
+	"github.com/im-naren/savitar/pkg/vector"
+)
+
+// Gateway exposes a ClusterManager as HTTP/JSON, mirroring the gRPC Server
+// surface for clients that would rather not speak protobuf.
+type Gateway struct {
+	cm *cluster.ClusterManager
+}
+
+// NewGateway wraps cm for HTTP/JSON access.
+func NewGateway(cm *cluster.ClusterManager) *Gateway {
+	return &Gateway{cm: cm}
+}
+
+// Handler returns the http.Handler implementing the gateway's routes.
+func (g *Gateway) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/vectors", g.handleVectors)
+	mux.HandleFunc("/v1/vectors/batch", g.handleBatchPut)
+	mux.HandleFunc("/v1/vectors/", g.handleVectorByID)
+	mux.HandleFunc("/v1/search", g.handleSearch)
+	return mux
+}
+
+func (g *Gateway) handleVectors(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var v vector.Vector
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := v.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := g.cm.AddVector(v); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (g *Gateway) handleBatchPut(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var vectors []vector.Vector
+	if err := json.NewDecoder(r.Body).Decode(&vectors); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := struct {
+		Accepted int      `json:"accepted"`
+		Errors   []string `json:"errors,omitempty"`
+	}{}
+	for _, v := range vectors {
+		if err := v.Validate(); err != nil {
+			resp.Errors = append(resp.Errors, v.ID+": "+err.Error())
+			continue
+		}
+		if err := g.cm.AddVector(v); err != nil {
+			resp.Errors = append(resp.Errors, v.ID+": "+err.Error())
+			continue
+		}
+		resp.Accepted++
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (g *Gateway) handleVectorByID(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Path[len("/v1/vectors/"):]
+	if id == "" {
+		http.Error(w, "missing vector id", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		v, err := g.cm.GetVector(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(v)
+	case http.MethodDelete:
+		if err := g.cm.DeleteVector(id); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Query  []float64       `json:"query"`
+		TopN   int             `json:"top_n"`
+		Ef     int             `json:"ef"`
+		Filter json.RawMessage `json:"filter"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Ef <= 0 {
+		req.Ef = defaultSearchEf
+	}
+
+	var pred filter.Predicate
+	if len(req.Filter) > 0 {
+		parsed, err := filter.ParseJSON(req.Filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		pred = parsed
+	}
+
+	results, err := g.cm.SearchVectors(req.Query, req.TopN, req.Ef, pred)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(results)
+}
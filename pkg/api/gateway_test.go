@@ -0,0 +1,131 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/im-naren/savitar/pkg/cluster"
+)
+
+func newTestGateway() *Gateway {
+	return NewGateway(cluster.NewClusterManager(2))
+}
+
+func TestGatewayPutGetDelete(t *testing.T) {
+	g := newTestGateway()
+	h := g.Handler()
+
+	body := bytes.NewBufferString(`{"ID":"a","Data":[1,2]}`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/vectors", body)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /v1/vectors status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/vectors/a", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /v1/vectors/a status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/v1/vectors/a", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE /v1/vectors/a status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/vectors/a", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("GET /v1/vectors/a after delete status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestGatewayVectorByIDMissingID(t *testing.T) {
+	g := newTestGateway()
+	rec := httptest.NewRecorder()
+	g.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/vectors/", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("GET /v1/vectors/ status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGatewayRejectsUnsupportedMethod(t *testing.T) {
+	g := newTestGateway()
+	rec := httptest.NewRecorder()
+	g.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/vectors", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET /v1/vectors status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestGatewayBatchPut(t *testing.T) {
+	g := newTestGateway()
+	body := bytes.NewBufferString(`[{"ID":"a","Data":[1]},{"ID":"b","Data":[]}]`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/vectors/batch", body)
+	rec := httptest.NewRecorder()
+	g.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /v1/vectors/batch status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var resp struct {
+		Accepted int      `json:"accepted"`
+		Errors   []string `json:"errors,omitempty"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if resp.Accepted != 1 || len(resp.Errors) != 1 {
+		t.Fatalf("response = %+v, want 1 accepted and 1 error", resp)
+	}
+}
+
+func TestGatewaySearch(t *testing.T) {
+	g := newTestGateway()
+	h := g.Handler()
+
+	for _, body := range []string{
+		`{"ID":"near","Data":[1,0]}`,
+		`{"ID":"far","Data":[100,100]}`,
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/vectors", bytes.NewBufferString(body)))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("POST /v1/vectors status = %d, want %d", rec.Code, http.StatusCreated)
+		}
+	}
+
+	searchBody := bytes.NewBufferString(`{"query":[2,0],"top_n":1}`)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/search", searchBody))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /v1/search status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var results []struct {
+		Vector struct {
+			ID string `json:"ID"`
+		} `json:"Vector"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(results) != 1 || results[0].Vector.ID != "near" {
+		t.Fatalf("search results = %v, want [near]", results)
+	}
+}
+
+func TestGatewaySearchRejectsInvalidFilter(t *testing.T) {
+	g := newTestGateway()
+	body := bytes.NewBufferString(`{"query":[0,0],"top_n":1,"filter":{"bogus":1}}`)
+	rec := httptest.NewRecorder()
+	g.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/search", body))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
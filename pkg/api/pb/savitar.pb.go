@@ -0,0 +1,115 @@
+// Package pb is the hand-maintained Go counterpart of proto/savitar.proto.
+// There is no protoc/buf generation step in this repo; edit this file and
+// keep it in sync with the .proto by hand.
+
+package pb
+
+import "fmt"
+
+// VectorProto is the wire representation of vector.Vector.
+type VectorProto struct {
+	Id   string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Data []float64 `protobuf:"fixed64,2,rep,packed,name=data,proto3" json:"data,omitempty"`
+	// MetaJson is a JSON-encoded object, since metadata values can be
+	// strings, numbers, or bools rather than just strings.
+	MetaJson string `protobuf:"bytes,3,opt,name=meta_json,json=metaJson,proto3" json:"meta_json,omitempty"`
+}
+
+func (x *VectorProto) Reset()         { *x = VectorProto{} }
+func (x *VectorProto) String() string { return fmt.Sprintf("%+v", *x) }
+func (*VectorProto) ProtoMessage()    {}
+
+type PutRequest struct {
+	Vector *VectorProto `protobuf:"bytes,1,opt,name=vector,proto3" json:"vector,omitempty"`
+}
+
+func (x *PutRequest) Reset()         { *x = PutRequest{} }
+func (x *PutRequest) String() string { return fmt.Sprintf("%+v", *x) }
+func (*PutRequest) ProtoMessage()    {}
+
+type PutResponse struct {
+	Ok bool `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+}
+
+func (x *PutResponse) Reset()         { *x = PutResponse{} }
+func (x *PutResponse) String() string { return fmt.Sprintf("%+v", *x) }
+func (*PutResponse) ProtoMessage()    {}
+
+type BatchPutRequest struct {
+	Vectors []*VectorProto `protobuf:"bytes,1,rep,name=vectors,proto3" json:"vectors,omitempty"`
+}
+
+func (x *BatchPutRequest) Reset()         { *x = BatchPutRequest{} }
+func (x *BatchPutRequest) String() string { return fmt.Sprintf("%+v", *x) }
+func (*BatchPutRequest) ProtoMessage()    {}
+
+type BatchPutResponse struct {
+	Accepted int32    `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Errors   []string `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *BatchPutResponse) Reset()         { *x = BatchPutResponse{} }
+func (x *BatchPutResponse) String() string { return fmt.Sprintf("%+v", *x) }
+func (*BatchPutResponse) ProtoMessage()    {}
+
+type GetRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetRequest) Reset()         { *x = GetRequest{} }
+func (x *GetRequest) String() string { return fmt.Sprintf("%+v", *x) }
+func (*GetRequest) ProtoMessage()    {}
+
+type GetResponse struct {
+	Vector *VectorProto `protobuf:"bytes,1,opt,name=vector,proto3" json:"vector,omitempty"`
+}
+
+func (x *GetResponse) Reset()         { *x = GetResponse{} }
+func (x *GetResponse) String() string { return fmt.Sprintf("%+v", *x) }
+func (*GetResponse) ProtoMessage()    {}
+
+type DeleteRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteRequest) Reset()         { *x = DeleteRequest{} }
+func (x *DeleteRequest) String() string { return fmt.Sprintf("%+v", *x) }
+func (*DeleteRequest) ProtoMessage()    {}
+
+type DeleteResponse struct {
+	Ok bool `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+}
+
+func (x *DeleteResponse) Reset()         { *x = DeleteResponse{} }
+func (x *DeleteResponse) String() string { return fmt.Sprintf("%+v", *x) }
+func (*DeleteResponse) ProtoMessage()    {}
+
+type SearchRequest struct {
+	Query []float64 `protobuf:"fixed64,1,rep,packed,name=query,proto3" json:"query,omitempty"`
+	TopN  int32     `protobuf:"varint,2,opt,name=top_n,json=topN,proto3" json:"top_n,omitempty"`
+	Ef    int32     `protobuf:"varint,3,opt,name=ef,proto3" json:"ef,omitempty"`
+	// FilterJson is a JSON-encoded filter expression tree; see
+	// filter.ParseJSON. Empty matches every vector.
+	FilterJson string `protobuf:"bytes,4,opt,name=filter_json,json=filterJson,proto3" json:"filter_json,omitempty"`
+}
+
+func (x *SearchRequest) Reset()         { *x = SearchRequest{} }
+func (x *SearchRequest) String() string { return fmt.Sprintf("%+v", *x) }
+func (*SearchRequest) ProtoMessage()    {}
+
+type SearchResult struct {
+	Vector   *VectorProto `protobuf:"bytes,1,opt,name=vector,proto3" json:"vector,omitempty"`
+	Distance float64      `protobuf:"fixed64,2,opt,name=distance,proto3" json:"distance,omitempty"`
+}
+
+func (x *SearchResult) Reset()         { *x = SearchResult{} }
+func (x *SearchResult) String() string { return fmt.Sprintf("%+v", *x) }
+func (*SearchResult) ProtoMessage()    {}
+
+type SearchResponse struct {
+	Results []*SearchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *SearchResponse) Reset()         { *x = SearchResponse{} }
+func (x *SearchResponse) String() string { return fmt.Sprintf("%+v", *x) }
+func (*SearchResponse) ProtoMessage()    {}
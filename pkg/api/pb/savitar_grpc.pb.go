@@ -0,0 +1,257 @@
+// Package pb: this file is the hand-maintained Go counterpart of the
+// Savitar service in proto/savitar.proto. There is no protoc-gen-go-grpc
+// step in this repo; edit this file and keep it in sync with the .proto by
+// hand.
+
+package pb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SavitarClient is the client API for the Savitar service.
+type SavitarClient interface {
+	Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error)
+	BatchPut(ctx context.Context, in *BatchPutRequest, opts ...grpc.CallOption) (*BatchPutResponse, error)
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (Savitar_SearchStreamClient, error)
+}
+
+type savitarClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewSavitarClient builds a client bound to the given connection.
+func NewSavitarClient(cc grpc.ClientConnInterface) SavitarClient {
+	return &savitarClient{cc}
+}
+
+func (c *savitarClient) Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error) {
+	out := new(PutResponse)
+	if err := c.cc.Invoke(ctx, "/savitar.Savitar/Put", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *savitarClient) BatchPut(ctx context.Context, in *BatchPutRequest, opts ...grpc.CallOption) (*BatchPutResponse, error) {
+	out := new(BatchPutResponse)
+	if err := c.cc.Invoke(ctx, "/savitar.Savitar/BatchPut", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *savitarClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	out := new(GetResponse)
+	if err := c.cc.Invoke(ctx, "/savitar.Savitar/Get", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *savitarClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	if err := c.cc.Invoke(ctx, "/savitar.Savitar/Delete", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *savitarClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	if err := c.cc.Invoke(ctx, "/savitar.Savitar/Search", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *savitarClient) SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (Savitar_SearchStreamClient, error) {
+	stream, err := c.cc.(grpc.ClientConnInterface).NewStream(ctx, &_Savitar_serviceDesc.Streams[0], "/savitar.Savitar/SearchStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &savitarSearchStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// Savitar_SearchStreamClient is the stream handle returned by SearchStream.
+type Savitar_SearchStreamClient interface {
+	Recv() (*SearchResult, error)
+	grpc.ClientStream
+}
+
+type savitarSearchStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *savitarSearchStreamClient) Recv() (*SearchResult, error) {
+	m := new(SearchResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SavitarServer is the server API for the Savitar service.
+type SavitarServer interface {
+	Put(context.Context, *PutRequest) (*PutResponse, error)
+	BatchPut(context.Context, *BatchPutRequest) (*BatchPutResponse, error)
+	Get(context.Context, *GetRequest) (*GetResponse, error)
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	SearchStream(*SearchRequest, Savitar_SearchStreamServer) error
+}
+
+// UnimplementedSavitarServer can be embedded to satisfy forward compatibility.
+type UnimplementedSavitarServer struct{}
+
+func (UnimplementedSavitarServer) Put(context.Context, *PutRequest) (*PutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Put not implemented")
+}
+func (UnimplementedSavitarServer) BatchPut(context.Context, *BatchPutRequest) (*BatchPutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchPut not implemented")
+}
+func (UnimplementedSavitarServer) Get(context.Context, *GetRequest) (*GetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedSavitarServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedSavitarServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedSavitarServer) SearchStream(*SearchRequest, Savitar_SearchStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchStream not implemented")
+}
+
+// RegisterSavitarServer registers srv with the given gRPC server.
+func RegisterSavitarServer(s *grpc.Server, srv SavitarServer) {
+	s.RegisterService(&_Savitar_serviceDesc, srv)
+}
+
+// Savitar_SearchStreamServer is the stream handle passed to the server-side
+// SearchStream implementation.
+type Savitar_SearchStreamServer interface {
+	Send(*SearchResult) error
+	grpc.ServerStream
+}
+
+type savitarSearchStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *savitarSearchStreamServer) Send(m *SearchResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Savitar_SearchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SavitarServer).SearchStream(m, &savitarSearchStreamServer{stream})
+}
+
+var _Savitar_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "savitar.Savitar",
+	HandlerType: (*SavitarServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Put", Handler: _Savitar_Put_Handler},
+		{MethodName: "BatchPut", Handler: _Savitar_BatchPut_Handler},
+		{MethodName: "Get", Handler: _Savitar_Get_Handler},
+		{MethodName: "Delete", Handler: _Savitar_Delete_Handler},
+		{MethodName: "Search", Handler: _Savitar_Search_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "SearchStream", Handler: _Savitar_SearchStream_Handler, ServerStreams: true},
+	},
+	Metadata: "proto/savitar.proto",
+}
+
+func _Savitar_Put_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SavitarServer).Put(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/savitar.Savitar/Put"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SavitarServer).Put(ctx, req.(*PutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Savitar_BatchPut_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchPutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SavitarServer).BatchPut(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/savitar.Savitar/BatchPut"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SavitarServer).BatchPut(ctx, req.(*BatchPutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Savitar_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SavitarServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/savitar.Savitar/Get"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SavitarServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Savitar_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SavitarServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/savitar.Savitar/Delete"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SavitarServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Savitar_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SavitarServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/savitar.Savitar/Search"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SavitarServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
@@ -0,0 +1,151 @@
+// Package api exposes a ClusterManager over gRPC and, via Gateway, a
+// matching HTTP/JSON surface.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/im-naren/savitar/pkg/api/pb"
+	"github.com/im-naren/savitar/pkg/cluster"
+	"github.com/im-naren/savitar/pkg/filter"
+	"github.com/im-naren/savitar/pkg/shard"
+	"github.com/im-naren/savitar/pkg/vector"
+)
+
+// defaultSearchEf is used when a SearchRequest does not specify one.
+const defaultSearchEf = 64
+
+// Server implements pb.SavitarServer on top of a ClusterManager.
+type Server struct {
+	pb.UnimplementedSavitarServer
+	cm *cluster.ClusterManager
+}
+
+// NewServer wraps cm so it can be served over gRPC.
+func NewServer(cm *cluster.ClusterManager) *Server {
+	return &Server{cm: cm}
+}
+
+func fromProto(v *pb.VectorProto) (vector.Vector, error) {
+	vec := vector.Vector{ID: v.Id, Data: v.Data}
+	if v.MetaJson != "" {
+		if err := json.Unmarshal([]byte(v.MetaJson), &vec.Meta); err != nil {
+			return vector.Vector{}, fmt.Errorf("api: invalid meta_json: %w", err)
+		}
+	}
+	return vec, nil
+}
+
+func toProto(v vector.Vector) *pb.VectorProto {
+	p := &pb.VectorProto{Id: v.ID, Data: v.Data}
+	if len(v.Meta) > 0 {
+		if b, err := json.Marshal(v.Meta); err == nil {
+			p.MetaJson = string(b)
+		}
+	}
+	return p
+}
+
+// Put adds a single vector to the cluster.
+func (s *Server) Put(ctx context.Context, req *pb.PutRequest) (*pb.PutResponse, error) {
+	v, err := fromProto(req.Vector)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.cm.AddVector(v); err != nil {
+		return nil, err
+	}
+	return &pb.PutResponse{Ok: true}, nil
+}
+
+// BatchPut adds many vectors, continuing past individual failures and
+// reporting each one back to the caller rather than aborting the batch.
+func (s *Server) BatchPut(ctx context.Context, req *pb.BatchPutRequest) (*pb.BatchPutResponse, error) {
+	resp := &pb.BatchPutResponse{}
+	for _, vp := range req.Vectors {
+		v, err := fromProto(vp)
+		if err != nil {
+			resp.Errors = append(resp.Errors, vp.Id+": "+err.Error())
+			continue
+		}
+		if err := v.Validate(); err != nil {
+			resp.Errors = append(resp.Errors, v.ID+": "+err.Error())
+			continue
+		}
+		if err := s.cm.AddVector(v); err != nil {
+			resp.Errors = append(resp.Errors, v.ID+": "+err.Error())
+			continue
+		}
+		resp.Accepted++
+	}
+	return resp, nil
+}
+
+// Get retrieves a single vector by ID.
+func (s *Server) Get(ctx context.Context, req *pb.GetRequest) (*pb.GetResponse, error) {
+	v, err := s.cm.GetVector(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetResponse{Vector: toProto(v)}, nil
+}
+
+// Delete removes a vector by ID.
+func (s *Server) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+	if err := s.cm.DeleteVector(req.Id); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteResponse{Ok: true}, nil
+}
+
+// Search returns the topN nearest neighbors of the query vector, buffering
+// every shard's results before responding.
+func (s *Server) Search(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResponse, error) {
+	ef := int(req.Ef)
+	if ef <= 0 {
+		ef = defaultSearchEf
+	}
+	pred, err := parseSearchFilter(req.FilterJson)
+	if err != nil {
+		return nil, err
+	}
+	hits, err := s.cm.SearchVectors(req.Query, int(req.TopN), ef, pred)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.SearchResponse{Results: make([]*pb.SearchResult, len(hits))}
+	for i, hit := range hits {
+		resp.Results[i] = &pb.SearchResult{Vector: toProto(hit.Vector), Distance: hit.Distance}
+	}
+	return resp, nil
+}
+
+// SearchStream emits results as soon as they're available rather than
+// waiting for every shard to finish, unlike the unary Search above.
+func (s *Server) SearchStream(req *pb.SearchRequest, stream pb.Savitar_SearchStreamServer) error {
+	ef := int(req.Ef)
+	if ef <= 0 {
+		ef = defaultSearchEf
+	}
+	pred, err := parseSearchFilter(req.FilterJson)
+	if err != nil {
+		return err
+	}
+	return s.cm.StreamSearchVectors(stream.Context(), req.Query, int(req.TopN), ef, pred, func(hit shard.SearchResult) error {
+		return stream.Send(&pb.SearchResult{Vector: toProto(hit.Vector), Distance: hit.Distance})
+	})
+}
+
+// parseSearchFilter parses a SearchRequest's filter_json, if present.
+func parseSearchFilter(filterJSON string) (filter.Predicate, error) {
+	if filterJSON == "" {
+		return nil, nil
+	}
+	return filter.ParseJSON([]byte(filterJSON))
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/im-naren/savitar/pkg/api/pb"
+	"github.com/im-naren/savitar/pkg/cluster"
+)
+
+func newTestServer() *Server {
+	return NewServer(cluster.NewClusterManager(2))
+}
+
+func TestServerPutGetDelete(t *testing.T) {
+	s := newTestServer()
+	ctx := context.Background()
+
+	if _, err := s.Put(ctx, &pb.PutRequest{Vector: &pb.VectorProto{Id: "a", Data: []float64{1, 2}}}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, err := s.Get(ctx, &pb.GetRequest{Id: "a"})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Vector.Id != "a" {
+		t.Fatalf("Get() = %+v, want id \"a\"", got.Vector)
+	}
+
+	if _, err := s.Delete(ctx, &pb.DeleteRequest{Id: "a"}); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get(ctx, &pb.GetRequest{Id: "a"}); err == nil {
+		t.Fatal("expected an error getting a deleted vector")
+	}
+}
+
+func TestServerPutRejectsInvalidVector(t *testing.T) {
+	s := newTestServer()
+	_, err := s.Put(context.Background(), &pb.PutRequest{Vector: &pb.VectorProto{Id: "a"}})
+	if err == nil {
+		t.Fatal("expected an error for an empty vector")
+	}
+}
+
+func TestServerPutRejectsInvalidMetaJSON(t *testing.T) {
+	s := newTestServer()
+	req := &pb.PutRequest{Vector: &pb.VectorProto{Id: "a", Data: []float64{1}, MetaJson: "not json"}}
+	if _, err := s.Put(context.Background(), req); err == nil {
+		t.Fatal("expected an error for invalid meta_json")
+	}
+}
+
+func TestServerBatchPutReportsPerVectorErrors(t *testing.T) {
+	s := newTestServer()
+	req := &pb.BatchPutRequest{Vectors: []*pb.VectorProto{
+		{Id: "good", Data: []float64{1, 2}},
+		{Id: "bad"},
+	}}
+	resp, err := s.BatchPut(context.Background(), req)
+	if err != nil {
+		t.Fatalf("BatchPut: %v", err)
+	}
+	if resp.Accepted != 1 {
+		t.Errorf("Accepted = %d, want 1", resp.Accepted)
+	}
+	if len(resp.Errors) != 1 {
+		t.Errorf("Errors = %v, want one entry", resp.Errors)
+	}
+}
+
+func TestServerSearch(t *testing.T) {
+	s := newTestServer()
+	ctx := context.Background()
+	for _, v := range []*pb.VectorProto{
+		{Id: "near", Data: []float64{1, 0}},
+		{Id: "far", Data: []float64{100, 100}},
+	} {
+		if _, err := s.Put(ctx, &pb.PutRequest{Vector: v}); err != nil {
+			t.Fatalf("Put(%s): %v", v.Id, err)
+		}
+	}
+
+	resp, err := s.Search(ctx, &pb.SearchRequest{Query: []float64{2, 0}, TopN: 1})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Vector.Id != "near" {
+		t.Fatalf("Search() = %v, want [near]", resp.Results)
+	}
+}
+
+func TestServerSearchRejectsInvalidFilter(t *testing.T) {
+	s := newTestServer()
+	_, err := s.Search(context.Background(), &pb.SearchRequest{Query: []float64{0, 0}, TopN: 1, FilterJson: "not json"})
+	if err == nil {
+		t.Fatal("expected an error for invalid filter_json")
+	}
+}
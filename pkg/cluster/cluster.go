@@ -2,50 +2,744 @@
 package cluster
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/hashicorp/raft"
+	"github.com/im-naren/savitar/pkg/filter"
+	"github.com/im-naren/savitar/pkg/hashring"
 	"github.com/im-naren/savitar/pkg/node"
+	"github.com/im-naren/savitar/pkg/replication"
+	"github.com/im-naren/savitar/pkg/shard"
+	"github.com/im-naren/savitar/pkg/shardrule"
 	"github.com/im-naren/savitar/pkg/vector"
+	"sort"
 	"sync"
 )
 
+// handoffEntry records a vector that is mid-migration between two nodes;
+// writes for it are double-written to both until the migration completes.
+type handoffEntry struct {
+	from, to string
+}
+
 // ClusterManager coordinates distributed nodes and handles operations
 type ClusterManager struct {
-	nodes []*node.Node
-	mu    sync.RWMutex
+	nodes     []*node.Node
+	nodesByID map[string]*node.Node
+	ring      *hashring.Ring
+	handoff   map[string]handoffEntry
+	mu        sync.RWMutex
+
+	// rule, when non-nil, overrides the hash ring for placement: vectors
+	// are routed to whichever node key its shardKey(id, meta) function
+	// returns instead of wherever the ring would put them.
+	rule *shardrule.Rule
+	// placement records the node a rule routed each vector's id to, since
+	// a read or delete by id alone has no metadata to hand the rule for
+	// recomputation. Unused when rule is nil, as the ring needs only id.
+	placement   map[string]string
+	placementMu sync.RWMutex
+
+	// replicas, when non-nil, means the cluster's shard is a single group
+	// replicated across every entry in nodes via Raft rather than an
+	// independent partition per node. Splitting the keyspace across
+	// several such replicated groups is left for a future change.
+	replicas []*replication.ReplicatedShard
+
+	// metric is the distance function every shard this cluster creates for
+	// itself (in NewClusterManager/NewClusterManagerFromConfig and in
+	// AddNode) uses. It plays no role for nodes a caller builds and hands
+	// to NewClusterManagerFromNodes.
+	metric vector.Metric
 }
 
-// NewClusterManager initializes a cluster manager with a specified number of nodes
+// newEmptyClusterManager builds a ClusterManager with its ring, handoff, and
+// placement bookkeeping initialized but no nodes yet, shared by every
+// non-replicated constructor below.
+func newEmptyClusterManager(metric vector.Metric) *ClusterManager {
+	return &ClusterManager{
+		nodesByID: make(map[string]*node.Node),
+		ring:      hashring.New(0),
+		handoff:   make(map[string]handoffEntry),
+		placement: make(map[string]string),
+		metric:    metric,
+	}
+}
+
+// NewClusterManager initializes a cluster manager with a specified number of
+// nodes, placed on a consistent hash ring with virtual nodes so adding or
+// removing a node later only reassigns a small fraction of the keyspace.
+// Each node's shard uses the default cosine metric and full precision; use
+// NewClusterManagerFromConfig for a different metric or NewClusterManagerFromNodes
+// for quantization, which has no string-configurable equivalent.
 func NewClusterManager(nodeCount int) *ClusterManager {
-	nodes := make([]*node.Node, nodeCount)
+	cm := newEmptyClusterManager(vector.CosineMetric{})
 	for i := 0; i < nodeCount; i++ {
-		nodes[i] = node.NewNode(fmt.Sprintf("node-%d", i))
+		id := fmt.Sprintf("node-%d", i)
+		n := node.NewNodeWithShard(id, shard.NewShardWithMetric(cm.metric))
+		cm.nodes = append(cm.nodes, n)
+		cm.nodesByID[id] = n
+		cm.ring.AddNode(id)
+	}
+	return cm
+}
+
+// Config configures optional ClusterManager behavior beyond the defaults
+// NewClusterManager uses.
+type Config struct {
+	// NodeCount is the number of nodes to start with, placed on the
+	// consistent hash ring.
+	NodeCount int
+	// ShardRule, if non-empty, is JavaScript source defining a
+	// shardKey(id, meta) function (see pkg/shardrule) that overrides the
+	// hash ring for placement. Left empty, placement falls back to
+	// consistent hashing.
+	ShardRule string
+	// Metric names the distance function every node's shard uses, resolved
+	// via vector.MetricByName (see that function for supported names).
+	// Empty falls back to cosine, matching NewClusterManager.
+	Metric string
+}
+
+// NewClusterManagerFromConfig builds a cluster from cfg, resolving
+// cfg.Metric and compiling and loading cfg.ShardRule, if set, as the
+// cluster's placement rule. With cfg.Metric empty and no shard rule, it
+// behaves exactly like NewClusterManager(cfg.NodeCount).
+func NewClusterManagerFromConfig(cfg Config) (*ClusterManager, error) {
+	metric, err := metricOrDefault(cfg.Metric)
+	if err != nil {
+		return nil, err
+	}
+
+	cm := newEmptyClusterManager(metric)
+	for i := 0; i < cfg.NodeCount; i++ {
+		id := fmt.Sprintf("node-%d", i)
+		n := node.NewNodeWithShard(id, shard.NewShardWithMetric(cm.metric))
+		cm.nodes = append(cm.nodes, n)
+		cm.nodesByID[id] = n
+		cm.ring.AddNode(id)
+	}
+
+	if cfg.ShardRule != "" {
+		rule, err := shardrule.New(cfg.ShardRule)
+		if err != nil {
+			return nil, fmt.Errorf("cluster: loading shard rule: %w", err)
+		}
+		cm.rule = rule
 	}
-	return &ClusterManager{nodes: nodes}
+	return cm, nil
 }
 
-// AddVector adds a vector to the cluster by selecting an appropriate node
+// metricOrDefault resolves name via vector.MetricByName, falling back to
+// cosine for an empty name rather than treating it as an unknown metric.
+func metricOrDefault(name string) (vector.Metric, error) {
+	if name == "" {
+		return vector.CosineMetric{}, nil
+	}
+	metric, err := vector.MetricByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("cluster: %w", err)
+	}
+	return metric, nil
+}
+
+// NewClusterManagerFromNodes assembles a cluster from pre-built nodes on a
+// consistent hash ring, for callers who need shard configuration Config
+// can't express as a string, such as a quantized shard (NewQuantizedShard
+// and NewScalarQuantizedShard both take an already-trained quantizer, which
+// has no Config equivalent). Build each node with node.NewNodeWithShard and
+// the desired shard constructor, then pass them here; AddNode on the result
+// still creates new nodes with cosine, full-precision shards, so mixing
+// metrics across nodes added later is the caller's responsibility.
+func NewClusterManagerFromNodes(nodes []*node.Node) (*ClusterManager, error) {
+	if len(nodes) == 0 {
+		return nil, errors.New("cluster: at least one node is required")
+	}
+	cm := newEmptyClusterManager(vector.CosineMetric{})
+	for _, n := range nodes {
+		if _, exists := cm.nodesByID[n.ID]; exists {
+			return nil, fmt.Errorf("cluster: duplicate node id %q", n.ID)
+		}
+		cm.nodes = append(cm.nodes, n)
+		cm.nodesByID[n.ID] = n
+		cm.ring.AddNode(n.ID)
+	}
+	return cm, nil
+}
+
+// NewReplicatedClusterManager builds a cluster whose shard is replicated
+// replicationFactor ways via Raft, so AddVector/UpdateVector/DeleteVector
+// survive the loss of any minority of replicas. Writes are routed to the
+// current leader; GetVector (and GetVectorConsistent) choose between a
+// stale-ok local read and a linearizable leader read.
+func NewReplicatedClusterManager(replicationFactor int) (*ClusterManager, error) {
+	if replicationFactor < 1 {
+		return nil, errors.New("cluster: replication factor must be >= 1")
+	}
+
+	nodes := make([]*node.Node, replicationFactor)
+	shards := make([]*shard.Shard, replicationFactor)
+	transports := make([]*raft.InmemTransport, replicationFactor)
+	servers := make([]raft.Server, replicationFactor)
+
+	for i := 0; i < replicationFactor; i++ {
+		id := raft.ServerID(fmt.Sprintf("node-%d", i))
+		addr, transport := raft.NewInmemTransport(raft.ServerAddress(id))
+		nodes[i] = node.NewNode(string(id))
+		shards[i] = shard.NewShard()
+		transports[i] = transport
+		servers[i] = raft.Server{ID: id, Address: addr}
+	}
+	for i, t := range transports {
+		for j, other := range transports {
+			if i != j {
+				t.Connect(other.LocalAddr(), other)
+			}
+		}
+	}
+
+	replicas := make([]*replication.ReplicatedShard, replicationFactor)
+	for i, s := range shards {
+		rs, err := replication.NewReplicatedShard(s, replication.Config{
+			LocalID:   servers[i].ID,
+			Bootstrap: i == 0,
+			Servers:   servers,
+			Transport: transports[i],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cluster: starting replica %d: %w", i, err)
+		}
+		replicas[i] = rs
+	}
+
+	return &ClusterManager{nodes: nodes, replicas: replicas}, nil
+}
+
+// leaderReplica returns whichever replica currently holds Raft leadership.
+func (cm *ClusterManager) leaderReplica() (*replication.ReplicatedShard, error) {
+	for _, r := range cm.replicas {
+		if r.IsLeader() {
+			return r, nil
+		}
+	}
+	return nil, errors.New("cluster: no replica currently holds leadership")
+}
+
+// AddVector adds a vector to the cluster by placing it on its owning node
+// (chosen by the shard rule if one is configured, else by the hash ring). If
+// the vector's key is mid-migration, it is double-written to both the source
+// and destination node so a concurrent RemoveNode/AddNode can't drop it.
 func (cm *ClusterManager) AddVector(v vector.Vector) error {
-	node := cm.selectNode(v.ID)
-	if node == nil {
+	if cm.replicas != nil {
+		leader, err := cm.leaderReplica()
+		if err != nil {
+			return err
+		}
+		return leader.AddVector(v)
+	}
+	meta := v.Meta
+	if meta == nil {
+		// A nil meta marks a read to ownerNodeID; a write always consults the rule.
+		meta = map[string]any{}
+	}
+	targets := cm.writeTargets(v.ID, meta)
+	if len(targets) == 0 {
 		return errors.New("no node available")
 	}
-	return node.AddVector(v)
+	if err := targets[0].AddVector(v); err != nil {
+		return err
+	}
+	// Secondary targets are handoff destinations/sources mid-migration;
+	// best-effort, since the authoritative copy above already succeeded.
+	for _, n := range targets[1:] {
+		_ = n.AddVector(v)
+	}
+	return nil
 }
 
-// GetVector retrieves a vector from the appropriate node
+// UpdateVector updates a vector in the cluster by selecting an appropriate node
+func (cm *ClusterManager) UpdateVector(v vector.Vector) error {
+	if cm.replicas != nil {
+		leader, err := cm.leaderReplica()
+		if err != nil {
+			return err
+		}
+		return leader.UpdateVector(v)
+	}
+
+	var previousOwner string
+	if cm.rule != nil {
+		cm.placementMu.RLock()
+		previousOwner = cm.placement[v.ID]
+		cm.placementMu.RUnlock()
+	}
+
+	meta := v.Meta
+	if meta == nil {
+		// A nil meta marks a read to ownerNodeID; a write always consults the rule.
+		meta = map[string]any{}
+	}
+	targets := cm.writeTargets(v.ID, meta)
+	if len(targets) == 0 {
+		return errors.New("no node available")
+	}
+	// Like GetVector, try every target rather than just the first: during
+	// an AddNode/RemoveNode rebalance, targets[0] is the new ring/rule
+	// owner, which may not have received the vector from migrate() yet,
+	// while a handoff partner already has it.
+	var updated bool
+	var lastErr error
+	for _, n := range targets {
+		if err := n.UpdateVector(v); err != nil {
+			lastErr = err
+			continue
+		}
+		updated = true
+	}
+	if !updated {
+		return lastErr
+	}
+
+	// A shard rule can re-home a vector if the metadata it routes on
+	// changed; the ring never does, since it routes on id alone. Drop the
+	// stale copy left behind on its old owner so it doesn't keep showing
+	// up in scatter-gather search results.
+	if previousOwner != "" && previousOwner != targets[0].ID {
+		cm.mu.RLock()
+		old := cm.nodesByID[previousOwner]
+		cm.mu.RUnlock()
+		if old != nil {
+			_ = old.DeleteVector(v.ID)
+		}
+	}
+	return nil
+}
+
+// ReadConsistency selects how GetVectorConsistent resolves a read against a
+// replicated shard.
+type ReadConsistency int
+
+const (
+	// StaleRead serves from the local replica without consulting the leader.
+	StaleRead ReadConsistency = iota
+	// LinearizableRead always reads from the current Raft leader.
+	LinearizableRead
+)
+
+// GetVector retrieves a vector from the appropriate node. On a replicated
+// cluster this is a stale-ok read; use GetVectorConsistent for a
+// linearizable read against the leader.
 func (cm *ClusterManager) GetVector(id string) (vector.Vector, error) {
-	node := cm.selectNode(id)
-	if node == nil {
+	if cm.replicas != nil {
+		return cm.replicas[0].GetVector(id)
+	}
+	targets := cm.writeTargets(id, nil)
+	if len(targets) == 0 {
 		return vector.Vector{}, errors.New("vector not found")
 	}
-	return node.GetVector(id)
+	for _, n := range targets {
+		if v, err := n.GetVector(id); err == nil {
+			return v, nil
+		}
+	}
+	return vector.Vector{}, errors.New("vector not found")
+}
+
+// GetVectorConsistent reads id under the requested consistency level.
+// Non-replicated clusters ignore consistency and behave like GetVector.
+func (cm *ClusterManager) GetVectorConsistent(id string, consistency ReadConsistency) (vector.Vector, error) {
+	if cm.replicas == nil || consistency == StaleRead {
+		return cm.GetVector(id)
+	}
+	leader, err := cm.leaderReplica()
+	if err != nil {
+		return vector.Vector{}, err
+	}
+	return leader.GetVector(id)
+}
+
+// DeleteVector removes a vector from the appropriate node
+func (cm *ClusterManager) DeleteVector(id string) error {
+	if cm.replicas != nil {
+		leader, err := cm.leaderReplica()
+		if err != nil {
+			return err
+		}
+		return leader.DeleteVector(id)
+	}
+	targets := cm.writeTargets(id, nil)
+	if len(targets) == 0 {
+		return errors.New("no node available")
+	}
+	// Like GetVector, try every target rather than just the first: during
+	// an AddNode/RemoveNode rebalance, targets[0] is the new ring/rule
+	// owner, which may not have received the vector from migrate() yet,
+	// while a handoff partner already has it.
+	var deleted bool
+	var lastErr error
+	for _, n := range targets {
+		if err := n.DeleteVector(id); err != nil {
+			lastErr = err
+			continue
+		}
+		deleted = true
+	}
+	if !deleted {
+		return lastErr
+	}
+	if cm.rule != nil {
+		cm.placementMu.Lock()
+		delete(cm.placement, id)
+		cm.placementMu.Unlock()
+	}
+	return nil
+}
+
+// SearchVectors queries every node in the cluster concurrently and merges
+// the per-node results into the topN overall nearest neighbors of query
+// matching pred (a nil pred matches everything). ef controls the size of
+// the candidate list explored within each shard's HNSW index; it should be
+// >= topN for good recall.
+func (cm *ClusterManager) SearchVectors(query []float64, topN, ef int, pred filter.Predicate) ([]shard.SearchResult, error) {
+	if cm.replicas != nil {
+		return cm.replicas[0].Search(query, topN, ef, pred)
+	}
+
+	cm.mu.RLock()
+	nodes := make([]*node.Node, len(cm.nodes))
+	copy(nodes, cm.nodes)
+	cm.mu.RUnlock()
+
+	resultsChan := make(chan []shard.SearchResult, len(nodes))
+	errChan := make(chan error, len(nodes))
+	var wg sync.WaitGroup
+
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n *node.Node) {
+			defer wg.Done()
+			hits, err := n.SearchVectors(query, topN, ef, pred)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			resultsChan <- hits
+		}(n)
+	}
+
+	wg.Wait()
+	close(resultsChan)
+	close(errChan)
+
+	if len(errChan) > 0 {
+		return nil, <-errChan
+	}
+
+	var merged []shard.SearchResult
+	for hits := range resultsChan {
+		merged = append(merged, hits...)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Distance < merged[j].Distance
+	})
+	if len(merged) > topN {
+		merged = merged[:topN]
+	}
+	return merged, nil
 }
 
-// selectNode selects a node based on a hash of the vector ID (simple round-robin demo)
-func (cm *ClusterManager) selectNode(id string) *node.Node {
-	hash := int(len(id)) % len(cm.nodes)
-	return cm.nodes[hash]
+// StreamSearchVectors queries every node concurrently like SearchVectors, but
+// invokes emit for each hit as soon as its node returns rather than waiting
+// on every node to finish, so a caller serving a streaming RPC can start
+// sending results immediately. It stops early if ctx is canceled or emit
+// returns an error.
+func (cm *ClusterManager) StreamSearchVectors(ctx context.Context, query []float64, topN, ef int, pred filter.Predicate, emit func(shard.SearchResult) error) error {
+	if cm.replicas != nil {
+		hits, err := cm.replicas[0].Search(query, topN, ef, pred)
+		if err != nil {
+			return err
+		}
+		for _, hit := range hits {
+			if err := ctx.Err(); err != nil {
+				return nil
+			}
+			if err := emit(hit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cm.mu.RLock()
+	nodes := make([]*node.Node, len(cm.nodes))
+	copy(nodes, cm.nodes)
+	cm.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	errOnce := make(chan error, 1)
+
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n *node.Node) {
+			defer wg.Done()
+			hits, err := n.SearchVectors(query, topN, ef, pred)
+			if err != nil {
+				select {
+				case errOnce <- err:
+				default:
+				}
+				return
+			}
+			for _, hit := range hits {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+				if err := emit(hit); err != nil {
+					select {
+					case errOnce <- err:
+					default:
+					}
+					return
+				}
+			}
+		}(n)
+	}
+
+	wg.Wait()
+	select {
+	case err := <-errOnce:
+		return err
+	default:
+		return nil
+	}
 }
 
+// ownerNodeID returns the physical node id that owns key id. If a shard
+// rule is configured, meta (present for writes, nil for reads and deletes)
+// is used to (re)compute the placement via the rule, and the result is
+// cached in cm.placement so a later call with no metadata to hand can still
+// find it. Without a rule, placement falls back to the consistent hash
+// ring, which needs only id.
+func (cm *ClusterManager) ownerNodeID(id string, meta map[string]any) (string, error) {
+	if cm.rule == nil {
+		owner, ok := cm.ring.Get(id)
+		if !ok {
+			return "", errors.New("cluster: hash ring has no nodes")
+		}
+		return owner, nil
+	}
+	if meta != nil {
+		owner, err := cm.rule.Owner(id, meta)
+		if err != nil {
+			return "", fmt.Errorf("cluster: evaluating shard rule: %w", err)
+		}
+		cm.placementMu.Lock()
+		cm.placement[id] = owner
+		cm.placementMu.Unlock()
+		return owner, nil
+	}
+	cm.placementMu.RLock()
+	owner, ok := cm.placement[id]
+	cm.placementMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("cluster: no recorded placement for %q", id)
+	}
+	return owner, nil
+}
 
+// writeTargets returns the nodes a key at id should be written to/read from:
+// the node ownerNodeID currently assigns it to, plus a handoff partner (the
+// node it is migrating to or from) while a rebalance is in flight. The
+// owning node, if present, is always first so callers can treat it as
+// authoritative. meta is the vector's metadata for writes, used by a shard
+// rule if one is configured; pass nil for reads and deletes.
+func (cm *ClusterManager) writeTargets(id string, meta map[string]any) []*node.Node {
+	// Computed without cm.mu held: on a rule-based cluster this runs
+	// operator-supplied JavaScript, which must not block AddNode/RemoveNode
+	// or every other in-flight read and write on the cluster.
+	owner, ownerErr := cm.ownerNodeID(id, meta)
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	var targets []*node.Node
+	if ownerErr == nil {
+		if n := cm.nodesByID[owner]; n != nil {
+			targets = append(targets, n)
+		}
+	}
+	if h, ok := cm.handoff[id]; ok {
+		for _, nodeID := range []string{h.from, h.to} {
+			if n := cm.nodesByID[nodeID]; n != nil && !containsNode(targets, n) {
+				targets = append(targets, n)
+			}
+		}
+	}
+	return targets
+}
+
+func containsNode(nodes []*node.Node, n *node.Node) bool {
+	for _, existing := range nodes {
+		if existing == n {
+			return true
+		}
+	}
+	return false
+}
+
+// AddNode adds a new node to the cluster's hash ring and migrates to it
+// whichever keys the ring now assigns away from their current owners. Reads
+// and writes for a key being migrated are double-served from both its old
+// and new owner until the copy completes. It returns an error on a
+// replicated cluster, which has no ring, nodesByID, or handoff tracking to
+// rebalance in the first place.
+func (cm *ClusterManager) AddNode(ctx context.Context, id string) error {
+	if cm.replicas != nil {
+		return errors.New("cluster: AddNode is not supported on a replicated cluster")
+	}
+	cm.mu.Lock()
+	if _, exists := cm.nodesByID[id]; exists {
+		cm.mu.Unlock()
+		return fmt.Errorf("cluster: node %q already exists", id)
+	}
+	n := node.NewNodeWithShard(id, shard.NewShardWithMetric(cm.metric))
+	cm.nodesByID[id] = n
+	cm.nodes = append(cm.nodes, n)
+	cm.ring.AddNode(id)
+	// A shard rule's placement doesn't depend on ring membership, so
+	// adding a node never requires moving data that the rule already
+	// routed elsewhere; it only becomes a new destination the rule can
+	// reference going forward.
+	var moves []migration
+	if cm.rule == nil {
+		moves = cm.planMigration(id)
+	}
+	cm.mu.Unlock()
+
+	return cm.migrate(ctx, moves)
+}
+
+// RemoveNode migrates every key owned by id to its new ring owners, then
+// drops id from the cluster. It returns an error if removing id would leave
+// the ring empty, if the cluster uses a shard rule (a rule decides
+// placement independently of ring membership, so Savitar can't safely
+// infer where id's data should move to; repoint the rule and migrate its
+// data out of band instead), or if the cluster is replicated, which has no
+// ring, nodesByID, or handoff tracking to rebalance in the first place.
+func (cm *ClusterManager) RemoveNode(ctx context.Context, id string) error {
+	if cm.replicas != nil {
+		return errors.New("cluster: RemoveNode is not supported on a replicated cluster")
+	}
+	cm.mu.Lock()
+	if cm.rule != nil {
+		cm.mu.Unlock()
+		return errors.New("cluster: RemoveNode is not supported on a cluster using a shard rule")
+	}
+	leaving, exists := cm.nodesByID[id]
+	if !exists {
+		cm.mu.Unlock()
+		return fmt.Errorf("cluster: node %q not found", id)
+	}
+	if len(cm.nodesByID) == 1 {
+		cm.mu.Unlock()
+		return errors.New("cluster: cannot remove the last node")
+	}
+
+	vectors := leaving.AllVectors()
+	cm.ring.RemoveNode(id)
+
+	moves := make([]migration, 0, len(vectors))
+	for _, v := range vectors {
+		newOwnerID, ok := cm.ring.Get(v.ID)
+		if !ok || newOwnerID == id {
+			continue
+		}
+		cm.handoff[v.ID] = handoffEntry{from: id, to: newOwnerID}
+		moves = append(moves, migration{vector: v, from: leaving, to: cm.nodesByID[newOwnerID]})
+	}
+	cm.mu.Unlock()
+
+	if err := cm.migrate(ctx, moves); err != nil {
+		return fmt.Errorf("cluster: migrating off %q: %w", id, err)
+	}
+
+	cm.mu.Lock()
+	delete(cm.nodesByID, id)
+	for i, existing := range cm.nodes {
+		if existing == leaving {
+			cm.nodes = append(cm.nodes[:i], cm.nodes[i+1:]...)
+			break
+		}
+	}
+	cm.mu.Unlock()
+	return nil
+}
+
+// migration describes one key moving from one owning node to another during
+// a rebalance; migrate deletes it from from once the copy lands on to.
+type migration struct {
+	vector vector.Vector
+	from   *node.Node
+	to     *node.Node
+}
+
+// planMigration finds every vector on an existing node that the ring now
+// assigns to newNodeID instead, recording a handoff entry for each so reads
+// and writes see both copies until migrate copies it over. The caller must
+// hold cm.mu.
+func (cm *ClusterManager) planMigration(newNodeID string) []migration {
+	var moves []migration
+	for existingID, n := range cm.nodesByID {
+		if existingID == newNodeID {
+			continue
+		}
+		for _, v := range n.AllVectors() {
+			owner, ok := cm.ring.Get(v.ID)
+			if !ok || owner != newNodeID {
+				continue
+			}
+			cm.handoff[v.ID] = handoffEntry{from: existingID, to: newNodeID}
+			moves = append(moves, migration{vector: v, from: n, to: cm.nodesByID[newNodeID]})
+		}
+	}
+	return moves
+}
+
+// migrate copies each planned move's vector to its new owner, deletes it from
+// its old owner now that the new owner is authoritative, and clears the
+// handoff entry so writeTargets stops double-serving it. A destination that
+// already has the vector (e.g. left over from an earlier migration that
+// crashed after copying but before deleting the source) is treated as
+// success rather than aborting the rest of the batch.
+func (cm *ClusterManager) migrate(ctx context.Context, moves []migration) error {
+	for _, m := range moves {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if err := m.to.AddVector(m.vector); err != nil && !isVectorExistsErr(err) {
+			return fmt.Errorf("cluster: migrating %q to new node: %w", m.vector.ID, err)
+		}
+		if m.from != nil {
+			// Best-effort: the new owner above is already authoritative, so a
+			// missing/failed delete here just leaves a stale duplicate for
+			// the next compaction/migration to clean up rather than losing data.
+			_ = m.from.DeleteVector(m.vector.ID)
+		}
+		cm.mu.Lock()
+		delete(cm.handoff, m.vector.ID)
+		cm.mu.Unlock()
+	}
+	return nil
+}
+
+// isVectorExistsErr reports whether err is the "already exists" error
+// Shard/Node.AddVector return for a duplicate ID.
+func isVectorExistsErr(err error) bool {
+	return err != nil && err.Error() == "vector with this ID already exists"
+}
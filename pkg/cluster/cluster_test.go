@@ -0,0 +1,229 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/im-naren/savitar/pkg/node"
+	"github.com/im-naren/savitar/pkg/quantization"
+	"github.com/im-naren/savitar/pkg/shard"
+	"github.com/im-naren/savitar/pkg/vector"
+)
+
+func TestAddGetDeleteVector(t *testing.T) {
+	cm := NewClusterManager(3)
+
+	v := vector.Vector{ID: "a", Data: []float64{1, 2, 3}}
+	if err := cm.AddVector(v); err != nil {
+		t.Fatalf("AddVector: %v", err)
+	}
+
+	got, err := cm.GetVector("a")
+	if err != nil {
+		t.Fatalf("GetVector: %v", err)
+	}
+	if got.ID != "a" {
+		t.Fatalf("GetVector() = %+v, want ID \"a\"", got)
+	}
+
+	if err := cm.DeleteVector("a"); err != nil {
+		t.Fatalf("DeleteVector: %v", err)
+	}
+	if _, err := cm.GetVector("a"); err == nil {
+		t.Fatal("expected an error getting a deleted vector")
+	}
+}
+
+func TestSearchVectorsAcrossNodes(t *testing.T) {
+	cm := NewClusterManager(3)
+	for i := 0; i < 20; i++ {
+		v := vector.Vector{ID: fmt.Sprintf("v%d", i), Data: []float64{float64(i), 0}}
+		if err := cm.AddVector(v); err != nil {
+			t.Fatalf("AddVector(%s): %v", v.ID, err)
+		}
+	}
+
+	results, err := cm.SearchVectors([]float64{0, 0}, 3, 20, nil)
+	if err != nil {
+		t.Fatalf("SearchVectors: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("SearchVectors() returned %d results, want 3", len(results))
+	}
+	seen := make(map[string]bool)
+	for _, r := range results {
+		if seen[r.Vector.ID] {
+			t.Fatalf("SearchVectors() returned duplicate result for %q: %v", r.Vector.ID, results)
+		}
+		seen[r.Vector.ID] = true
+	}
+}
+
+// TestAddNodeThenRemoveNodeRoundTrip exercises the exact sequence from the
+// migrate() bug report: adding a node migrates keys onto it, and removing a
+// different node later must not leave a vector duplicated across the node
+// it migrated from and the node it migrated to.
+func TestAddNodeThenRemoveNodeRoundTrip(t *testing.T) {
+	cm := NewClusterManager(3)
+	ctx := context.Background()
+
+	for i := 0; i < 200; i++ {
+		v := vector.Vector{ID: fmt.Sprintf("id-%d", i), Data: []float64{float64(i), float64(i)}}
+		if err := cm.AddVector(v); err != nil {
+			t.Fatalf("AddVector(%s): %v", v.ID, err)
+		}
+	}
+
+	if err := cm.AddNode(ctx, "node-3"); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+
+	// Every vector should exist exactly once across the cluster now that the
+	// migration settled: no node should hold a duplicate of another node's
+	// vector.
+	total := 0
+	for _, n := range cm.nodes {
+		total += len(n.AllVectors())
+	}
+	if total != 200 {
+		t.Fatalf("total vectors across nodes after AddNode = %d, want 200 (no duplicates)", total)
+	}
+
+	if err := cm.RemoveNode(ctx, "node-1"); err != nil {
+		t.Fatalf("RemoveNode: %v", err)
+	}
+
+	for i := 0; i < 200; i++ {
+		id := fmt.Sprintf("id-%d", i)
+		if _, err := cm.GetVector(id); err != nil {
+			t.Fatalf("GetVector(%s) after RemoveNode: %v", id, err)
+		}
+	}
+
+	total = 0
+	for _, n := range cm.nodes {
+		total += len(n.AllVectors())
+	}
+	if total != 200 {
+		t.Fatalf("total vectors across nodes after RemoveNode = %d, want 200 (no duplicates, none lost)", total)
+	}
+}
+
+func TestRemoveNodeRejectsLastNode(t *testing.T) {
+	cm := NewClusterManager(1)
+	if err := cm.RemoveNode(context.Background(), "node-0"); err == nil {
+		t.Fatal("expected an error removing the last node")
+	}
+}
+
+func TestRemoveNodeRejectsUnknownNode(t *testing.T) {
+	cm := NewClusterManager(2)
+	if err := cm.RemoveNode(context.Background(), "node-99"); err == nil {
+		t.Fatal("expected an error removing an unknown node")
+	}
+}
+
+func TestAddNodeRejectsDuplicateID(t *testing.T) {
+	cm := NewClusterManager(2)
+	if err := cm.AddNode(context.Background(), "node-0"); err == nil {
+		t.Fatal("expected an error adding a node with an already-existing id")
+	}
+}
+
+func TestNewClusterManagerFromConfigWithShardRule(t *testing.T) {
+	cfg := Config{
+		NodeCount: 2,
+		ShardRule: `function shardKey(id, meta) { return "node-0"; }`,
+	}
+	cm, err := NewClusterManagerFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewClusterManagerFromConfig: %v", err)
+	}
+
+	v := vector.Vector{ID: "a", Data: []float64{1}, Meta: map[string]any{"k": "v"}}
+	if err := cm.AddVector(v); err != nil {
+		t.Fatalf("AddVector: %v", err)
+	}
+	if _, err := cm.GetVector("a"); err != nil {
+		t.Fatalf("GetVector: %v", err)
+	}
+
+	if err := cm.RemoveNode(context.Background(), "node-1"); err == nil {
+		t.Fatal("expected RemoveNode to be rejected on a shard-rule cluster")
+	}
+}
+
+func TestNewClusterManagerFromConfigWithMetric(t *testing.T) {
+	cm, err := NewClusterManagerFromConfig(Config{NodeCount: 2, Metric: "l2"})
+	if err != nil {
+		t.Fatalf("NewClusterManagerFromConfig: %v", err)
+	}
+
+	for _, v := range []vector.Vector{
+		{ID: "near", Data: []float64{1, 0}},
+		{ID: "far", Data: []float64{100, 100}},
+	} {
+		if err := cm.AddVector(v); err != nil {
+			t.Fatalf("AddVector(%s): %v", v.ID, err)
+		}
+	}
+
+	results, err := cm.SearchVectors([]float64{0, 0}, 1, 10, nil)
+	if err != nil {
+		t.Fatalf("SearchVectors: %v", err)
+	}
+	if len(results) != 1 || results[0].Vector.ID != "near" {
+		t.Fatalf("SearchVectors() = %v, want [near]", results)
+	}
+
+	// AddNode must keep using the configured metric for nodes it creates
+	// itself, not silently fall back to cosine.
+	if err := cm.AddNode(context.Background(), "node-2"); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+}
+
+func TestNewClusterManagerFromConfigRejectsUnknownMetric(t *testing.T) {
+	if _, err := NewClusterManagerFromConfig(Config{NodeCount: 1, Metric: "bogus"}); err == nil {
+		t.Fatal("expected an error for an unknown metric name")
+	}
+}
+
+func TestNewClusterManagerFromNodesWithQuantizedShard(t *testing.T) {
+	training := [][]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}}
+	quantizer, err := quantization.NewScalarQuantizer(training)
+	if err != nil {
+		t.Fatalf("NewScalarQuantizer: %v", err)
+	}
+
+	nodes := []*node.Node{
+		node.NewNodeWithShard("node-0", shard.NewScalarQuantizedShard(quantizer, vector.L2Metric{}, false)),
+		node.NewNodeWithShard("node-1", shard.NewShard()),
+	}
+	cm, err := NewClusterManagerFromNodes(nodes)
+	if err != nil {
+		t.Fatalf("NewClusterManagerFromNodes: %v", err)
+	}
+
+	v := vector.Vector{ID: "a", Data: []float64{1, 0}}
+	if err := cm.AddVector(v); err != nil {
+		t.Fatalf("AddVector: %v", err)
+	}
+	if _, err := cm.GetVector("a"); err != nil {
+		t.Fatalf("GetVector: %v", err)
+	}
+}
+
+func TestNewClusterManagerFromNodesRejectsEmpty(t *testing.T) {
+	if _, err := NewClusterManagerFromNodes(nil); err == nil {
+		t.Fatal("expected an error for no nodes")
+	}
+}
+
+func TestNewClusterManagerFromNodesRejectsDuplicateID(t *testing.T) {
+	nodes := []*node.Node{node.NewNode("node-0"), node.NewNode("node-0")}
+	if _, err := NewClusterManagerFromNodes(nodes); err == nil {
+		t.Fatal("expected an error for duplicate node ids")
+	}
+}
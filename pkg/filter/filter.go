@@ -0,0 +1,171 @@
+// Package filter implements a structured predicate tree for matching
+// against a vector's metadata, used to narrow search results before (or
+// instead of) distance computation.
+package filter
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Predicate evaluates whether meta satisfies some condition.
+type Predicate interface {
+	Match(meta map[string]any) bool
+}
+
+// And matches when every one of its predicates matches.
+type And []Predicate
+
+// Match implements Predicate.
+func (a And) Match(meta map[string]any) bool {
+	for _, p := range a {
+		if !p.Match(meta) {
+			return false
+		}
+	}
+	return true
+}
+
+// Or matches when at least one of its predicates matches.
+type Or []Predicate
+
+// Match implements Predicate.
+func (o Or) Match(meta map[string]any) bool {
+	for _, p := range o {
+		if p.Match(meta) {
+			return true
+		}
+	}
+	return false
+}
+
+// Not matches when its inner predicate does not.
+type Not struct{ Predicate Predicate }
+
+// Match implements Predicate.
+func (n Not) Match(meta map[string]any) bool {
+	return !n.Predicate.Match(meta)
+}
+
+// Eq matches when meta[Field] equals Value.
+type Eq struct {
+	Field string
+	Value any
+}
+
+// Match implements Predicate.
+func (e Eq) Match(meta map[string]any) bool {
+	v, ok := meta[e.Field]
+	return ok && equal(v, e.Value)
+}
+
+// In matches when meta[Field] equals one of Values.
+type In struct {
+	Field  string
+	Values []any
+}
+
+// Match implements Predicate.
+func (in In) Match(meta map[string]any) bool {
+	v, ok := meta[in.Field]
+	if !ok {
+		return false
+	}
+	for _, want := range in.Values {
+		if equal(v, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// NotIn matches when meta[Field] is absent or equal to none of Values.
+type NotIn struct {
+	Field  string
+	Values []any
+}
+
+// Match implements Predicate.
+func (n NotIn) Match(meta map[string]any) bool {
+	return !(In(n).Match(meta))
+}
+
+// cmpOp is a numeric comparison against a field's value.
+type cmpOp struct {
+	Field string
+	Value float64
+	cmp   func(v, want float64) bool
+}
+
+func (c cmpOp) Match(meta map[string]any) bool {
+	v, ok := asFloat(meta[c.Field])
+	return ok && c.cmp(v, c.Value)
+}
+
+// Gt matches when meta[Field] is a number greater than Value.
+func Gt(field string, value float64) Predicate {
+	return cmpOp{Field: field, Value: value, cmp: func(v, want float64) bool { return v > want }}
+}
+
+// Gte matches when meta[Field] is a number greater than or equal to Value.
+func Gte(field string, value float64) Predicate {
+	return cmpOp{Field: field, Value: value, cmp: func(v, want float64) bool { return v >= want }}
+}
+
+// Lt matches when meta[Field] is a number less than Value.
+func Lt(field string, value float64) Predicate {
+	return cmpOp{Field: field, Value: value, cmp: func(v, want float64) bool { return v < want }}
+}
+
+// Lte matches when meta[Field] is a number less than or equal to Value.
+func Lte(field string, value float64) Predicate {
+	return cmpOp{Field: field, Value: value, cmp: func(v, want float64) bool { return v <= want }}
+}
+
+// Prefix matches when meta[Field] is a string with the given prefix.
+type Prefix struct {
+	Field  string
+	Prefix string
+}
+
+// Match implements Predicate.
+func (p Prefix) Match(meta map[string]any) bool {
+	s, ok := meta[p.Field].(string)
+	return ok && strings.HasPrefix(s, p.Prefix)
+}
+
+// Exists matches when Field is present in meta at all.
+type Exists struct{ Field string }
+
+// Match implements Predicate.
+func (e Exists) Match(meta map[string]any) bool {
+	_, ok := meta[e.Field]
+	return ok
+}
+
+// equal compares two metadata values for equality without panicking. a and
+// b ultimately come from client-supplied JSON (meta and filter values
+// alike), so either can be a slice or map — dynamic types Go's == operator
+// cannot compare and panics on rather than reporting false. reflect.DeepEqual
+// handles those the same way it handles scalars, just more slowly.
+func equal(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// asFloat converts the numeric types produced by encoding/json (float64) or
+// commonly constructed directly in Go code (float32, int, int64) to a float64
+// for comparison. Other numeric kinds, such as int32 or uint, are reported
+// as not numeric.
+func asFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
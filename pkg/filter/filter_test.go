@@ -0,0 +1,87 @@
+package filter
+
+import "testing"
+
+func TestPredicates(t *testing.T) {
+	meta := map[string]any{
+		"category": "image",
+		"score":    0.8,
+		"tags":     []any{"a", "b"},
+	}
+
+	cases := []struct {
+		name string
+		p    Predicate
+		want bool
+	}{
+		{"eq match", Eq{Field: "category", Value: "image"}, true},
+		{"eq mismatch", Eq{Field: "category", Value: "video"}, false},
+		{"eq missing field", Eq{Field: "missing", Value: "image"}, false},
+		{"eq slice value", Eq{Field: "tags", Value: []any{"a", "b"}}, true},
+		{"in match", In{Field: "category", Values: []any{"video", "image"}}, true},
+		{"in mismatch", In{Field: "category", Values: []any{"video"}}, false},
+		{"not_in match", NotIn{Field: "category", Values: []any{"video"}}, true},
+		{"not_in mismatch", NotIn{Field: "category", Values: []any{"image"}}, false},
+		{"gt true", Gt("score", 0.5), true},
+		{"gt false", Gt("score", 0.9), false},
+		{"gte equal", Gte("score", 0.8), true},
+		{"lt true", Lt("score", 0.9), true},
+		{"lte equal", Lte("score", 0.8), true},
+		{"prefix match", Prefix{Field: "category", Prefix: "im"}, true},
+		{"prefix mismatch", Prefix{Field: "category", Prefix: "vi"}, false},
+		{"prefix non-string field", Prefix{Field: "score", Prefix: "0"}, false},
+		{"exists true", Exists{Field: "category"}, true},
+		{"exists false", Exists{Field: "missing"}, false},
+		{"and both true", And{Eq{Field: "category", Value: "image"}, Gt("score", 0.5)}, true},
+		{"and one false", And{Eq{Field: "category", Value: "image"}, Gt("score", 0.9)}, false},
+		{"or one true", Or{Eq{Field: "category", Value: "video"}, Gt("score", 0.5)}, true},
+		{"or both false", Or{Eq{Field: "category", Value: "video"}, Gt("score", 0.9)}, false},
+		{"not inverts", Not{Predicate: Eq{Field: "category", Value: "video"}}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.p.Match(meta); got != c.want {
+				t.Errorf("Match() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+// TestEqDoesNotPanicOnUncomparableValues guards against the Go == panic on
+// slice/map-typed metadata, which previously crashed the process on filter
+// values decoded from client JSON arrays.
+func TestEqDoesNotPanicOnUncomparableValues(t *testing.T) {
+	meta := map[string]any{"tags": []any{"a", "b"}}
+
+	if (Eq{Field: "tags", Value: []any{"a", "b"}}).Match(meta) != true {
+		t.Error("expected equal slices to match")
+	}
+	if (Eq{Field: "tags", Value: []any{"a", "c"}}).Match(meta) != false {
+		t.Error("expected differing slices not to match")
+	}
+	if (In{Field: "tags", Values: []any{[]any{"x"}, []any{"a", "b"}}}).Match(meta) != true {
+		t.Error("expected In to find the matching slice without panicking")
+	}
+}
+
+func TestAsFloat(t *testing.T) {
+	cases := []struct {
+		in     any
+		want   float64
+		wantOK bool
+	}{
+		{float64(1.5), 1.5, true},
+		{float32(2), 2, true},
+		{int(3), 3, true},
+		{int64(4), 4, true},
+		{"5", 0, false},
+		{nil, 0, false},
+	}
+	for _, c := range cases {
+		got, ok := asFloat(c.in)
+		if got != c.want || ok != c.wantOK {
+			t.Errorf("asFloat(%#v) = %v, %v; want %v, %v", c.in, got, ok, c.want, c.wantOK)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ParseJSON parses a filter expression like:
+//
+//	{"and":[{"eq":{"category":"image"}},{"gte":{"score":0.7}}]}
+//
+// into a Predicate tree. Each object must have exactly one key naming the
+// operator: and, or, not, eq, in, not_in, gt, gte, lt, lte, prefix, exists.
+func ParseJSON(data []byte) (Predicate, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("filter: invalid expression: %w", err)
+	}
+	return parseNode(raw)
+}
+
+func parseNode(raw map[string]json.RawMessage) (Predicate, error) {
+	if len(raw) != 1 {
+		return nil, fmt.Errorf("filter: expression must have exactly one operator, got %d", len(raw))
+	}
+	for op, body := range raw {
+		switch op {
+		case "and":
+			return parseCombinator(body, func(ps []Predicate) Predicate { return And(ps) })
+		case "or":
+			return parseCombinator(body, func(ps []Predicate) Predicate { return Or(ps) })
+		case "not":
+			var inner map[string]json.RawMessage
+			if err := json.Unmarshal(body, &inner); err != nil {
+				return nil, fmt.Errorf("filter: not: %w", err)
+			}
+			p, err := parseNode(inner)
+			if err != nil {
+				return nil, err
+			}
+			return Not{Predicate: p}, nil
+		case "eq":
+			field, value, err := parseFieldValue(body)
+			if err != nil {
+				return nil, fmt.Errorf("filter: eq: %w", err)
+			}
+			return Eq{Field: field, Value: value}, nil
+		case "in":
+			field, values, err := parseFieldValues(body)
+			if err != nil {
+				return nil, fmt.Errorf("filter: in: %w", err)
+			}
+			return In{Field: field, Values: values}, nil
+		case "not_in":
+			field, values, err := parseFieldValues(body)
+			if err != nil {
+				return nil, fmt.Errorf("filter: not_in: %w", err)
+			}
+			return NotIn{Field: field, Values: values}, nil
+		case "gt", "gte", "lt", "lte":
+			field, value, err := parseFieldFloat(body)
+			if err != nil {
+				return nil, fmt.Errorf("filter: %s: %w", op, err)
+			}
+			switch op {
+			case "gt":
+				return Gt(field, value), nil
+			case "gte":
+				return Gte(field, value), nil
+			case "lt":
+				return Lt(field, value), nil
+			default:
+				return Lte(field, value), nil
+			}
+		case "prefix":
+			var fields map[string]string
+			if err := json.Unmarshal(body, &fields); err != nil {
+				return nil, fmt.Errorf("filter: prefix: %w", err)
+			}
+			if len(fields) != 1 {
+				return nil, fmt.Errorf("filter: prefix: expected exactly one field, got %d", len(fields))
+			}
+			for field, prefix := range fields {
+				return Prefix{Field: field, Prefix: prefix}, nil
+			}
+			panic("unreachable")
+		case "exists":
+			var field string
+			if err := json.Unmarshal(body, &field); err != nil {
+				return nil, fmt.Errorf("filter: exists: %w", err)
+			}
+			return Exists{Field: field}, nil
+		default:
+			return nil, fmt.Errorf("filter: unknown operator %q", op)
+		}
+	}
+	panic("unreachable")
+}
+
+func parseCombinator(body json.RawMessage, build func([]Predicate) Predicate) (Predicate, error) {
+	var children []map[string]json.RawMessage
+	if err := json.Unmarshal(body, &children); err != nil {
+		return nil, fmt.Errorf("expected an array of expressions: %w", err)
+	}
+	predicates := make([]Predicate, 0, len(children))
+	for _, child := range children {
+		p, err := parseNode(child)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	return build(predicates), nil
+}
+
+func parseFieldValue(body json.RawMessage) (string, any, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", nil, err
+	}
+	if len(fields) != 1 {
+		return "", nil, fmt.Errorf("expected exactly one field, got %d", len(fields))
+	}
+	for field, value := range fields {
+		return field, value, nil
+	}
+	panic("unreachable")
+}
+
+func parseFieldValues(body json.RawMessage) (string, []any, error) {
+	var fields map[string][]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", nil, err
+	}
+	if len(fields) != 1 {
+		return "", nil, fmt.Errorf("expected exactly one field, got %d", len(fields))
+	}
+	for field, values := range fields {
+		return field, values, nil
+	}
+	panic("unreachable")
+}
+
+func parseFieldFloat(body json.RawMessage) (string, float64, error) {
+	var fields map[string]float64
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", 0, err
+	}
+	if len(fields) != 1 {
+		return "", 0, fmt.Errorf("expected exactly one field, got %d", len(fields))
+	}
+	for field, value := range fields {
+		return field, value, nil
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,59 @@
+package filter
+
+import "testing"
+
+func TestParseJSON(t *testing.T) {
+	meta := map[string]any{"category": "image", "score": 0.8}
+
+	cases := []struct {
+		name string
+		expr string
+		want bool
+	}{
+		{"eq", `{"eq":{"category":"image"}}`, true},
+		{"in", `{"in":{"category":["video","image"]}}`, true},
+		{"not_in", `{"not_in":{"category":["video"]}}`, true},
+		{"gt", `{"gt":{"score":0.5}}`, true},
+		{"gte", `{"gte":{"score":0.8}}`, true},
+		{"lt", `{"lt":{"score":0.9}}`, true},
+		{"lte", `{"lte":{"score":0.8}}`, true},
+		{"prefix", `{"prefix":{"category":"im"}}`, true},
+		{"exists", `{"exists":"category"}`, true},
+		{"not", `{"not":{"eq":{"category":"video"}}}`, true},
+		{"and", `{"and":[{"eq":{"category":"image"}},{"gte":{"score":0.7}}]}`, true},
+		{"or", `{"or":[{"eq":{"category":"video"}},{"gte":{"score":0.7}}]}`, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p, err := ParseJSON([]byte(c.expr))
+			if err != nil {
+				t.Fatalf("ParseJSON(%s): %v", c.expr, err)
+			}
+			if got := p.Match(meta); got != c.want {
+				t.Errorf("Match() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	cases := []string{
+		`not valid json`,
+		`{}`,
+		`{"eq":{"category":"image"},"gt":{"score":1}}`,
+		`{"bogus":{"category":"image"}}`,
+		`{"and":"not an array"}`,
+		`{"prefix":{"category":0}}`,
+		`{"eq":{"a":1,"b":2}}`,
+		`{"in":{"a":["x"],"b":["y"]}}`,
+		`{"not_in":{"a":["x"],"b":["y"]}}`,
+		`{"gt":{"a":1,"b":2}}`,
+		`{"prefix":{"a":"x","b":"y"}}`,
+	}
+	for _, expr := range cases {
+		if _, err := ParseJSON([]byte(expr)); err == nil {
+			t.Errorf("ParseJSON(%s): expected an error, got nil", expr)
+		}
+	}
+}
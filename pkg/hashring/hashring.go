@@ -0,0 +1,116 @@
+// Package hashring implements consistent hashing with virtual nodes, used
+// to place keys across a changing set of physical nodes or shards while
+// minimizing the fraction that must move when the set changes.
+package hashring
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// DefaultVirtualNodes is used when New is called with virtualNodes <= 0.
+const DefaultVirtualNodes = 128
+
+// Ring is a consistent hash ring over string node identifiers. Each
+// physical node is hashed into virtualNodes points on the ring so that
+// adding or removing a node only reassigns roughly 1/N of the keyspace
+// instead of colliding on something like len(id) % len(nodes).
+type Ring struct {
+	virtualNodes int
+
+	mu     sync.RWMutex
+	hashes []uint64 // sorted
+	owners map[uint64]string
+	nodes  map[string]bool
+}
+
+// New creates an empty ring. virtualNodes <= 0 uses DefaultVirtualNodes.
+func New(virtualNodes int) *Ring {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+	return &Ring{
+		virtualNodes: virtualNodes,
+		owners:       make(map[uint64]string),
+		nodes:        make(map[string]bool),
+	}
+}
+
+func hashOf(s string) uint64 {
+	return xxhash.Sum64String(s)
+}
+
+func vnodeKey(node string, i int) string {
+	return node + "#" + strconv.Itoa(i)
+}
+
+// AddNode adds a physical node and its virtual nodes to the ring. It is a
+// no-op if the node is already present.
+func (r *Ring) AddNode(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.nodes[node] {
+		return
+	}
+	r.nodes[node] = true
+	for i := 0; i < r.virtualNodes; i++ {
+		h := hashOf(vnodeKey(node, i))
+		r.owners[h] = node
+		r.hashes = append(r.hashes, h)
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+}
+
+// RemoveNode removes a physical node and all of its virtual nodes.
+func (r *Ring) RemoveNode(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.nodes[node] {
+		return
+	}
+	delete(r.nodes, node)
+
+	filtered := r.hashes[:0]
+	for _, h := range r.hashes {
+		if r.owners[h] == node {
+			delete(r.owners, h)
+			continue
+		}
+		filtered = append(filtered, h)
+	}
+	r.hashes = filtered
+}
+
+// Get returns the node owning key: the first virtual node clockwise of
+// key's hash on the ring, wrapping around to the first entry past the end.
+func (r *Ring) Get(key string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.hashes) == 0 {
+		return "", false
+	}
+	h := hashOf(key)
+	idx := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if idx == len(r.hashes) {
+		idx = 0
+	}
+	return r.owners[r.hashes[idx]], true
+}
+
+// Nodes returns the current set of physical nodes in the ring.
+func (r *Ring) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	nodes := make([]string, 0, len(r.nodes))
+	for n := range r.nodes {
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
@@ -0,0 +1,135 @@
+package hashring
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGetEmptyRing(t *testing.T) {
+	r := New(0)
+	if _, ok := r.Get("anything"); ok {
+		t.Fatal("Get on an empty ring should return ok=false")
+	}
+}
+
+func TestGetIsStableAcrossCalls(t *testing.T) {
+	r := New(0)
+	r.AddNode("a")
+	r.AddNode("b")
+	r.AddNode("c")
+
+	owner, ok := r.Get("some-key")
+	if !ok {
+		t.Fatal("expected an owner")
+	}
+	for i := 0; i < 10; i++ {
+		got, ok := r.Get("some-key")
+		if !ok || got != owner {
+			t.Fatalf("Get(%q) = %q, %v; want %q, true", "some-key", got, ok, owner)
+		}
+	}
+}
+
+func TestAddNodeIsIdempotent(t *testing.T) {
+	r := New(4)
+	r.AddNode("a")
+	before := len(r.hashes)
+	r.AddNode("a")
+	if len(r.hashes) != before {
+		t.Fatalf("re-adding a node changed the vnode count: %d -> %d", before, len(r.hashes))
+	}
+	if nodes := r.Nodes(); len(nodes) != 1 {
+		t.Fatalf("Nodes() = %v, want exactly one node", nodes)
+	}
+}
+
+func TestRemoveNodeDropsItsOwnership(t *testing.T) {
+	r := New(16)
+	r.AddNode("a")
+	r.AddNode("b")
+	r.RemoveNode("a")
+
+	if nodes := r.Nodes(); len(nodes) != 1 || nodes[0] != "b" {
+		t.Fatalf("Nodes() = %v, want [b]", nodes)
+	}
+	for _, owner := range r.owners {
+		if owner == "a" {
+			t.Fatal("removed node still owns a vnode")
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		owner, ok := r.Get(fmt.Sprintf("key-%d", i))
+		if !ok || owner != "b" {
+			t.Fatalf("Get(key-%d) = %q, %v; want b, true", i, owner, ok)
+		}
+	}
+}
+
+func TestRemoveNodeUnknownIsNoop(t *testing.T) {
+	r := New(0)
+	r.AddNode("a")
+	r.RemoveNode("does-not-exist")
+	if nodes := r.Nodes(); len(nodes) != 1 {
+		t.Fatalf("Nodes() = %v, want [a]", nodes)
+	}
+}
+
+// TestDistributionSpreadsKeys checks that with enough virtual nodes, keys
+// land on every physical node rather than piling onto one - the entire
+// point of hashing nodes to many points on the ring instead of one.
+func TestDistributionSpreadsKeys(t *testing.T) {
+	r := New(DefaultVirtualNodes)
+	nodes := []string{"a", "b", "c", "d"}
+	for _, n := range nodes {
+		r.AddNode(n)
+	}
+
+	counts := make(map[string]int)
+	const keys = 10000
+	for i := 0; i < keys; i++ {
+		owner, ok := r.Get(fmt.Sprintf("key-%d", i))
+		if !ok {
+			t.Fatalf("Get(key-%d) returned ok=false", i)
+		}
+		counts[owner]++
+	}
+
+	for _, n := range nodes {
+		if counts[n] == 0 {
+			t.Errorf("node %q received no keys out of %d", n, keys)
+		}
+	}
+}
+
+// TestAddNodeReassignsOnlyASlice mirrors the package doc's claim: adding a
+// node to an N-node ring should move roughly 1/(N+1) of the keyspace, not
+// something close to all of it.
+func TestAddNodeReassignsOnlyASlice(t *testing.T) {
+	r := New(DefaultVirtualNodes)
+	r.AddNode("a")
+	r.AddNode("b")
+	r.AddNode("c")
+
+	const keys = 10000
+	before := make(map[int]string, keys)
+	for i := 0; i < keys; i++ {
+		owner, _ := r.Get(fmt.Sprintf("key-%d", i))
+		before[i] = owner
+	}
+
+	r.AddNode("d")
+
+	moved := 0
+	for i := 0; i < keys; i++ {
+		owner, _ := r.Get(fmt.Sprintf("key-%d", i))
+		if owner != before[i] {
+			moved++
+		}
+	}
+
+	// Expect roughly 1/4 of keys to move; allow generous slack so the test
+	// isn't flaky, but it should be nowhere near "most of them".
+	if moved > keys/2 {
+		t.Fatalf("adding a 4th node moved %d/%d keys, want well under half", moved, keys)
+	}
+}
@@ -0,0 +1,511 @@
+// Package hnsw implements a Hierarchical Navigable Small World graph for
+// approximate nearest-neighbor search over float64 vectors.
+package hnsw
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// DistanceFunc computes the distance between two vectors; smaller is closer.
+type DistanceFunc func(a, b []float64) float64
+
+// Config controls the shape of the graph.
+type Config struct {
+	M              int // neighbors added per layer on insertion
+	Mmax           int // max neighbors per node at layers > 0 (defaults to M)
+	Mmax0          int // max neighbors per node at layer 0 (defaults to 2*M)
+	EfConstruction int // size of the dynamic candidate list during insertion
+	Distance       DistanceFunc
+}
+
+func (c *Config) withDefaults() Config {
+	cfg := *c
+	if cfg.M <= 0 {
+		cfg.M = 16
+	}
+	if cfg.Mmax <= 0 {
+		cfg.Mmax = cfg.M
+	}
+	if cfg.Mmax0 <= 0 {
+		cfg.Mmax0 = 2 * cfg.M
+	}
+	if cfg.EfConstruction <= 0 {
+		cfg.EfConstruction = 200
+	}
+	if cfg.Distance == nil {
+		cfg.Distance = EuclideanDistance
+	}
+	return cfg
+}
+
+// EuclideanDistance is the default DistanceFunc used when none is supplied.
+func EuclideanDistance(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// node is a single vector in the graph along with its per-layer adjacency.
+type node struct {
+	id         string
+	vector     []float64
+	level      int
+	neighbors  []map[string]*node // neighbors[layer] -> neighbor id -> neighbor
+	tombstoned bool
+	mu         sync.Mutex
+}
+
+// Graph is a concurrency-safe HNSW index.
+type Graph struct {
+	cfg   Config
+	mL    float64
+	mu    sync.RWMutex // guards nodes, entryPoint and maxLevel
+	nodes map[string]*node
+
+	entryPoint *node
+	maxLevel   int
+
+	rndMu sync.Mutex
+	rnd   *rand.Rand
+}
+
+// SearchResult is a single hit returned from Search.
+type SearchResult struct {
+	ID       string
+	Vector   []float64
+	Distance float64
+}
+
+// NewGraph creates an empty graph using the given configuration.
+func NewGraph(cfg Config) *Graph {
+	resolved := cfg.withDefaults()
+	return &Graph{
+		cfg:      resolved,
+		mL:       1 / math.Log(float64(resolved.M)),
+		nodes:    make(map[string]*node),
+		maxLevel: -1,
+		rnd:      rand.New(rand.NewSource(1)),
+	}
+}
+
+// randomLevel draws a level from a geometric distribution with parameter mL,
+// matching the original HNSW paper's level assignment.
+func (g *Graph) randomLevel() int {
+	g.rndMu.Lock()
+	defer g.rndMu.Unlock()
+	return int(math.Floor(-math.Log(g.rnd.Float64()) * g.mL))
+}
+
+// AddVector inserts a vector into the graph, or replaces it in place if the
+// ID already exists.
+func (g *Graph) AddVector(id string, vector []float64) error {
+	if len(vector) == 0 {
+		return errors.New("hnsw: vector must not be empty")
+	}
+
+	level := g.randomLevel()
+	n := &node{
+		id:        id,
+		vector:    vector,
+		level:     level,
+		neighbors: make([]map[string]*node, level+1),
+	}
+	for i := range n.neighbors {
+		n.neighbors[i] = make(map[string]*node)
+	}
+
+	g.mu.Lock()
+	existing, hadExisting := g.nodes[id]
+	if hadExisting {
+		existing.mu.Lock()
+		existing.tombstoned = true
+		existing.mu.Unlock()
+	}
+	g.nodes[id] = n
+
+	// Replacing the current entry point would otherwise leave it pointing at
+	// a tombstoned node that's no longer even in g.nodes (its id now maps to
+	// n), permanently orphaning the entry point. Fall back to the
+	// highest-level surviving node, same as CompactTombstones does.
+	if hadExisting && g.entryPoint == existing {
+		g.entryPoint = nil
+		g.maxLevel = -1
+		for _, other := range g.nodes {
+			if other == n || other.tombstoned {
+				continue
+			}
+			if g.entryPoint == nil || other.level > g.maxLevel {
+				g.entryPoint = other
+				g.maxLevel = other.level
+			}
+		}
+	}
+
+	entry := g.entryPoint
+	maxLevel := g.maxLevel
+	if entry == nil {
+		g.entryPoint = n
+		g.maxLevel = level
+		g.mu.Unlock()
+		return nil
+	}
+	if level > maxLevel {
+		g.maxLevel = level
+	}
+	g.mu.Unlock()
+
+	// Greedily descend from the top layer to find the closest entry point
+	// for the layers at which this node participates.
+	curr := entry
+	currDist := g.cfg.Distance(vector, curr.vector)
+	for layer := maxLevel; layer > level; layer-- {
+		curr, currDist = g.greedyClosest(curr, currDist, vector, layer)
+	}
+
+	candidates := []*node{curr}
+	for layer := min(level, maxLevel); layer >= 0; layer-- {
+		found := g.searchLayer(vector, candidates, g.cfg.EfConstruction, layer, nil)
+		neighbors := g.selectNeighborsHeuristic(vector, found, g.cfg.M)
+
+		mmax := g.cfg.Mmax
+		if layer == 0 {
+			mmax = g.cfg.Mmax0
+		}
+
+		for _, nb := range neighbors {
+			g.link(n, nb, layer, mmax)
+			g.link(nb, n, layer, mmax)
+		}
+		candidates = found
+	}
+
+	if level > maxLevel {
+		g.mu.Lock()
+		g.entryPoint = n
+		g.mu.Unlock()
+	}
+	return nil
+}
+
+// link adds a directed edge from a to b at layer, pruning a's neighbor list
+// back down to mmax using the heuristic selection if it grows too large.
+func (g *Graph) link(a, b *node, layer, mmax int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if layer >= len(a.neighbors) {
+		return
+	}
+	a.neighbors[layer][b.id] = b
+	if len(a.neighbors[layer]) <= mmax {
+		return
+	}
+
+	candidates := make([]*node, 0, len(a.neighbors[layer]))
+	for _, nb := range a.neighbors[layer] {
+		candidates = append(candidates, nb)
+	}
+	pruned := g.selectNeighborsHeuristic(a.vector, candidates, mmax)
+	kept := make(map[string]*node, len(pruned))
+	for _, nb := range pruned {
+		kept[nb.id] = nb
+	}
+	a.neighbors[layer] = kept
+}
+
+// selectNeighborsHeuristic picks up to m candidates, preferring ones that are
+// diverse with respect to already-selected neighbors rather than simply the
+// m closest (the heuristic from the HNSW paper, section 4).
+func (g *Graph) selectNeighborsHeuristic(query []float64, candidates []*node, m int) []*node {
+	type scored struct {
+		n *node
+		d float64
+	}
+	pool := make([]scored, 0, len(candidates))
+	for _, c := range candidates {
+		if c.tombstoned {
+			continue
+		}
+		pool = append(pool, scored{c, g.cfg.Distance(query, c.vector)})
+	}
+	// sort ascending by distance to query
+	for i := 1; i < len(pool); i++ {
+		for j := i; j > 0 && pool[j].d < pool[j-1].d; j-- {
+			pool[j], pool[j-1] = pool[j-1], pool[j]
+		}
+	}
+
+	selected := make([]*node, 0, m)
+	for _, cand := range pool {
+		if len(selected) >= m {
+			break
+		}
+		diverse := true
+		for _, s := range selected {
+			if g.cfg.Distance(cand.n.vector, s.vector) < cand.d {
+				diverse = false
+				break
+			}
+		}
+		if diverse {
+			selected = append(selected, cand.n)
+		}
+	}
+	return selected
+}
+
+// greedyClosest walks from curr towards the single closest neighbor to query
+// at the given layer (used to descend through the upper sparse layers).
+func (g *Graph) greedyClosest(curr *node, currDist float64, query []float64, layer int) (*node, float64) {
+	for {
+		curr.mu.Lock()
+		neighbors := make([]*node, 0, len(curr.neighbors[layer]))
+		if layer < len(curr.neighbors) {
+			for _, nb := range curr.neighbors[layer] {
+				neighbors = append(neighbors, nb)
+			}
+		}
+		curr.mu.Unlock()
+
+		improved := false
+		for _, nb := range neighbors {
+			if nb.tombstoned {
+				continue
+			}
+			d := g.cfg.Distance(query, nb.vector)
+			if d < currDist {
+				curr, currDist = nb, d
+				improved = true
+			}
+		}
+		if !improved {
+			return curr, currDist
+		}
+	}
+}
+
+// heapItem pairs a node with its distance to the query. searchLayer keeps
+// them in a minHeap for the "candidates to explore" set and in a maxHeap for
+// the current best results.
+type heapItem struct {
+	n *node
+	d float64
+}
+
+type minHeap []heapItem
+
+func (h minHeap) Len() int            { return len(h) }
+func (h minHeap) Less(i, j int) bool  { return h[i].d < h[j].d }
+func (h minHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x interface{}) { *h = append(*h, x.(heapItem)) }
+func (h *minHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+type maxHeap []heapItem
+
+func (h maxHeap) Len() int            { return len(h) }
+func (h maxHeap) Less(i, j int) bool  { return h[i].d > h[j].d }
+func (h maxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x interface{}) { *h = append(*h, x.(heapItem)) }
+func (h *maxHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// searchLayer runs the bounded best-first search described in the HNSW paper:
+// a candidate min-heap drives exploration while a result max-heap holds the
+// current ef best results seen so far, so the worst result can be evicted in
+// O(log ef) once a closer candidate is found. accept, if non-nil, is
+// consulted before a node is admitted to the result heap so a caller can
+// restrict results (e.g. to those matching a metadata filter) without
+// disturbing graph traversal, which still walks rejected nodes to reach
+// their neighbors.
+func (g *Graph) searchLayer(query []float64, entryPoints []*node, ef, layer int, accept func(id string) bool) []*node {
+	visited := make(map[string]bool)
+	candidates := &minHeap{}
+	results := &maxHeap{}
+
+	admit := func(n *node, d float64) {
+		if accept != nil && !accept(n.id) {
+			return
+		}
+		heap.Push(results, heapItem{n, d})
+		if results.Len() > ef {
+			heap.Pop(results)
+		}
+	}
+
+	for _, ep := range entryPoints {
+		if ep.tombstoned || visited[ep.id] {
+			continue
+		}
+		d := g.cfg.Distance(query, ep.vector)
+		visited[ep.id] = true
+		heap.Push(candidates, heapItem{ep, d})
+		admit(ep, d)
+	}
+
+	for candidates.Len() > 0 {
+		nearest := heap.Pop(candidates).(heapItem)
+		if results.Len() >= ef && nearest.d > (*results)[0].d {
+			break
+		}
+
+		nearest.n.mu.Lock()
+		var neighbors []*node
+		if layer < len(nearest.n.neighbors) {
+			for _, nb := range nearest.n.neighbors[layer] {
+				neighbors = append(neighbors, nb)
+			}
+		}
+		nearest.n.mu.Unlock()
+
+		for _, nb := range neighbors {
+			if visited[nb.id] || nb.tombstoned {
+				continue
+			}
+			visited[nb.id] = true
+			d := g.cfg.Distance(query, nb.vector)
+			if results.Len() < ef || d < (*results)[0].d {
+				heap.Push(candidates, heapItem{nb, d})
+				admit(nb, d)
+			}
+		}
+	}
+
+	found := make([]*node, results.Len())
+	for i := len(found) - 1; i >= 0; i-- {
+		found[i] = heap.Pop(results).(heapItem).n
+	}
+	return found
+}
+
+// DeleteVector tombstones a vector so it is excluded from future searches.
+// The underlying node and its edges are reclaimed by CompactTombstones.
+func (g *Graph) DeleteVector(id string) error {
+	g.mu.RLock()
+	n, ok := g.nodes[id]
+	g.mu.RUnlock()
+	if !ok {
+		return errors.New("hnsw: vector not found")
+	}
+	n.mu.Lock()
+	n.tombstoned = true
+	n.mu.Unlock()
+	return nil
+}
+
+// Search returns up to topN nearest neighbors of query, exploring an ef-sized
+// candidate list at layer 0 (ef should be >= topN for good recall). accept,
+// if non-nil, is consulted for every candidate id and only matching vectors
+// are returned; graph traversal still passes through rejected nodes, so a
+// restrictive accept can mean fewer than topN results are found even when
+// more exist deeper in the graph.
+func (g *Graph) Search(query []float64, topN, ef int, accept func(id string) bool) ([]SearchResult, error) {
+	if ef < topN {
+		ef = topN
+	}
+
+	g.mu.RLock()
+	entry := g.entryPoint
+	maxLevel := g.maxLevel
+	g.mu.RUnlock()
+
+	if entry == nil {
+		return nil, nil
+	}
+
+	curr := entry
+	currDist := g.cfg.Distance(query, curr.vector)
+	for layer := maxLevel; layer > 0; layer-- {
+		curr, currDist = g.greedyClosest(curr, currDist, query, layer)
+	}
+	_ = currDist
+
+	found := g.searchLayer(query, []*node{curr}, ef, 0, accept)
+
+	results := make([]SearchResult, 0, len(found))
+	for _, n := range found {
+		if n.tombstoned {
+			continue
+		}
+		results = append(results, SearchResult{ID: n.id, Vector: n.vector, Distance: g.cfg.Distance(query, n.vector)})
+	}
+	for i := 1; i < len(results); i++ {
+		for j := i; j > 0 && results[j].Distance < results[j-1].Distance; j-- {
+			results[j], results[j-1] = results[j-1], results[j]
+		}
+	}
+	if len(results) > topN {
+		results = results[:topN]
+	}
+	return results, nil
+}
+
+// CompactTombstones removes tombstoned nodes from the graph along with every
+// edge pointing at them, reassigning the entry point if needed. It returns
+// the number of nodes reclaimed.
+func (g *Graph) CompactTombstones() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	removed := 0
+	for id, n := range g.nodes {
+		n.mu.Lock()
+		dead := n.tombstoned
+		n.mu.Unlock()
+		if !dead {
+			continue
+		}
+		delete(g.nodes, id)
+		removed++
+	}
+	if removed == 0 {
+		return 0
+	}
+
+	for _, n := range g.nodes {
+		n.mu.Lock()
+		for layer := range n.neighbors {
+			for id, nb := range n.neighbors[layer] {
+				if nb.tombstoned {
+					delete(n.neighbors[layer], id)
+				}
+			}
+		}
+		n.mu.Unlock()
+	}
+
+	if g.entryPoint == nil || g.entryPoint.tombstoned {
+		g.entryPoint = nil
+		g.maxLevel = -1
+		for _, n := range g.nodes {
+			if g.entryPoint == nil || n.level > g.maxLevel {
+				g.entryPoint = n
+				g.maxLevel = n.level
+			}
+		}
+	}
+	return removed
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
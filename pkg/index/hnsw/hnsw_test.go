@@ -0,0 +1,240 @@
+package hnsw
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newTestGraph() *Graph {
+	return NewGraph(Config{M: 8, EfConstruction: 32})
+}
+
+func TestSearchEmptyGraph(t *testing.T) {
+	g := newTestGraph()
+	results, err := g.Search([]float64{1, 2, 3}, 5, 10, nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("Search on empty graph = %v, want none", results)
+	}
+}
+
+func TestAddVectorRejectsEmptyVector(t *testing.T) {
+	g := newTestGraph()
+	if err := g.AddVector("a", nil); err == nil {
+		t.Fatal("expected an error for an empty vector")
+	}
+}
+
+func TestSearchFindsNearestNeighbor(t *testing.T) {
+	g := newTestGraph()
+	points := map[string][]float64{
+		"origin": {0, 0},
+		"near":   {1, 0},
+		"far":    {100, 100},
+	}
+	for id, v := range points {
+		if err := g.AddVector(id, v); err != nil {
+			t.Fatalf("AddVector(%s): %v", id, err)
+		}
+	}
+
+	results, err := g.Search([]float64{0, 0}, 1, 10, nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "origin" {
+		t.Fatalf("Search() = %v, want [origin]", results)
+	}
+}
+
+func TestSearchResultsAreSortedByDistance(t *testing.T) {
+	g := newTestGraph()
+	for i := 0; i < 20; i++ {
+		if err := g.AddVector(fmt.Sprintf("v%d", i), []float64{float64(i), 0}); err != nil {
+			t.Fatalf("AddVector: %v", err)
+		}
+	}
+
+	results, err := g.Search([]float64{0, 0}, 5, 50, nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Distance < results[i-1].Distance {
+			t.Fatalf("results not sorted by distance: %v", results)
+		}
+	}
+}
+
+func TestAddVectorReplacesExistingID(t *testing.T) {
+	g := newTestGraph()
+	if err := g.AddVector("a", []float64{0, 0}); err != nil {
+		t.Fatalf("AddVector: %v", err)
+	}
+	if err := g.AddVector("a", []float64{100, 100}); err != nil {
+		t.Fatalf("AddVector (replace): %v", err)
+	}
+
+	results, err := g.Search([]float64{100, 100}, 1, 10, nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].Distance > 1e-9 {
+		t.Fatalf("Search() = %v, want a single exact match at {100,100}", results)
+	}
+}
+
+func TestDeleteVectorExcludesFromSearch(t *testing.T) {
+	g := newTestGraph()
+	if err := g.AddVector("a", []float64{0, 0}); err != nil {
+		t.Fatalf("AddVector: %v", err)
+	}
+	if err := g.AddVector("b", []float64{1, 1}); err != nil {
+		t.Fatalf("AddVector: %v", err)
+	}
+
+	if err := g.DeleteVector("a"); err != nil {
+		t.Fatalf("DeleteVector: %v", err)
+	}
+
+	results, err := g.Search([]float64{0, 0}, 2, 10, nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	for _, r := range results {
+		if r.ID == "a" {
+			t.Fatalf("deleted vector %q still returned: %v", "a", results)
+		}
+	}
+}
+
+func TestDeleteVectorUnknownID(t *testing.T) {
+	g := newTestGraph()
+	if err := g.DeleteVector("missing"); err == nil {
+		t.Fatal("expected an error deleting an unknown id")
+	}
+}
+
+func TestSearchAcceptFilter(t *testing.T) {
+	g := newTestGraph()
+	for i := 0; i < 10; i++ {
+		if err := g.AddVector(fmt.Sprintf("v%d", i), []float64{float64(i), 0}); err != nil {
+			t.Fatalf("AddVector: %v", err)
+		}
+	}
+
+	accept := func(id string) bool { return id == "v7" }
+	results, err := g.Search([]float64{0, 0}, 3, 50, accept)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "v7" {
+		t.Fatalf("Search() with accept filter = %v, want only [v7]", results)
+	}
+}
+
+func TestCompactTombstonesReclaimsDeletedNodes(t *testing.T) {
+	g := newTestGraph()
+	for i := 0; i < 10; i++ {
+		if err := g.AddVector(fmt.Sprintf("v%d", i), []float64{float64(i), 0}); err != nil {
+			t.Fatalf("AddVector: %v", err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if err := g.DeleteVector(fmt.Sprintf("v%d", i)); err != nil {
+			t.Fatalf("DeleteVector: %v", err)
+		}
+	}
+
+	removed := g.CompactTombstones()
+	if removed != 5 {
+		t.Fatalf("CompactTombstones() = %d, want 5", removed)
+	}
+	if got := len(g.nodes); got != 5 {
+		t.Fatalf("len(g.nodes) = %d, want 5", got)
+	}
+	for _, n := range g.nodes {
+		for _, layer := range n.neighbors {
+			for id := range layer {
+				if _, ok := g.nodes[id]; !ok {
+					t.Fatalf("remaining node %q has a dangling edge to removed node %q", n.id, id)
+				}
+			}
+		}
+	}
+
+	// The graph should still be fully searchable after compaction.
+	results, err := g.Search([]float64{9, 0}, 1, 10, nil)
+	if err != nil {
+		t.Fatalf("Search after compaction: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "v9" {
+		t.Fatalf("Search() after compaction = %v, want [v9]", results)
+	}
+}
+
+func TestCompactTombstonesNoop(t *testing.T) {
+	g := newTestGraph()
+	if err := g.AddVector("a", []float64{0, 0}); err != nil {
+		t.Fatalf("AddVector: %v", err)
+	}
+	if removed := g.CompactTombstones(); removed != 0 {
+		t.Fatalf("CompactTombstones() = %d, want 0", removed)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	cfg := Config{M: 8, EfConstruction: 32}
+	g := NewGraph(cfg)
+	for i := 0; i < 30; i++ {
+		if err := g.AddVector(fmt.Sprintf("v%d", i), []float64{float64(i), float64(i) * float64(i)}); err != nil {
+			t.Fatalf("AddVector: %v", err)
+		}
+	}
+	if err := g.DeleteVector("v3"); err != nil {
+		t.Fatalf("DeleteVector: %v", err)
+	}
+
+	data, err := g.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	loaded, err := LoadGraph(data, cfg)
+	if err != nil {
+		t.Fatalf("LoadGraph: %v", err)
+	}
+
+	query := []float64{10, 100}
+	want, err := g.Search(query, 5, 50, nil)
+	if err != nil {
+		t.Fatalf("Search (original): %v", err)
+	}
+	got, err := loaded.Search(query, 5, 50, nil)
+	if err != nil {
+		t.Fatalf("Search (loaded): %v", err)
+	}
+	if len(want) != len(got) {
+		t.Fatalf("loaded graph returned %d results, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if want[i].ID != got[i].ID {
+			t.Errorf("result %d: loaded ID = %q, want %q", i, got[i].ID, want[i].ID)
+		}
+	}
+
+	// The tombstoned vector must not reappear after a round trip.
+	for _, r := range got {
+		if r.ID == "v3" {
+			t.Fatalf("tombstoned vector survived the snapshot round trip: %v", got)
+		}
+	}
+}
+
+func TestEuclideanDistance(t *testing.T) {
+	if d := EuclideanDistance([]float64{0, 0}, []float64{3, 4}); d != 5 {
+		t.Fatalf("EuclideanDistance() = %v, want 5", d)
+	}
+}
@@ -0,0 +1,122 @@
+package hnsw
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+	"math/rand"
+)
+
+// snapshotNode is the on-disk representation of a single graph node: the
+// vector plus, per layer, the IDs of its neighbors. Storing IDs rather than
+// pointers lets the graph be rebuilt without re-running insertion.
+type snapshotNode struct {
+	ID         string
+	Vector     []float64
+	Level      int
+	Neighbors  [][]string
+	Tombstoned bool
+}
+
+type snapshot struct {
+	M          int
+	Mmax       int
+	Mmax0      int
+	EntryPoint string
+	MaxLevel   int
+	Nodes      []snapshotNode
+}
+
+// Snapshot serializes the graph's adjacency lists so it can be restored
+// without re-indexing every vector on restart. The configured DistanceFunc
+// is not part of the snapshot and must be supplied again via Config when
+// loading.
+func (g *Graph) Snapshot() ([]byte, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	s := snapshot{
+		M:        g.cfg.M,
+		Mmax:     g.cfg.Mmax,
+		Mmax0:    g.cfg.Mmax0,
+		MaxLevel: g.maxLevel,
+		Nodes:    make([]snapshotNode, 0, len(g.nodes)),
+	}
+	if g.entryPoint != nil {
+		s.EntryPoint = g.entryPoint.id
+	}
+
+	for _, n := range g.nodes {
+		n.mu.Lock()
+		sn := snapshotNode{
+			ID:         n.id,
+			Vector:     n.vector,
+			Level:      n.level,
+			Tombstoned: n.tombstoned,
+			Neighbors:  make([][]string, len(n.neighbors)),
+		}
+		for layer, neighbors := range n.neighbors {
+			ids := make([]string, 0, len(neighbors))
+			for id := range neighbors {
+				ids = append(ids, id)
+			}
+			sn.Neighbors[layer] = ids
+		}
+		n.mu.Unlock()
+		s.Nodes = append(s.Nodes, sn)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadGraph rebuilds a graph from a Snapshot without re-running insertion;
+// edges are restored verbatim rather than recomputed via searchLayer.
+func LoadGraph(data []byte, cfg Config) (*Graph, error) {
+	var s snapshot
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&s); err != nil {
+		return nil, err
+	}
+
+	resolved := cfg.withDefaults()
+	if s.M > 0 {
+		resolved.M, resolved.Mmax, resolved.Mmax0 = s.M, s.Mmax, s.Mmax0
+	}
+
+	g := &Graph{
+		cfg:      resolved,
+		mL:       1 / math.Log(float64(resolved.M)),
+		nodes:    make(map[string]*node, len(s.Nodes)),
+		maxLevel: s.MaxLevel,
+		rnd:      rand.New(rand.NewSource(1)),
+	}
+
+	for _, sn := range s.Nodes {
+		g.nodes[sn.ID] = &node{
+			id:         sn.ID,
+			vector:     sn.Vector,
+			level:      sn.Level,
+			tombstoned: sn.Tombstoned,
+			neighbors:  make([]map[string]*node, len(sn.Neighbors)),
+		}
+	}
+	for _, sn := range s.Nodes {
+		n := g.nodes[sn.ID]
+		for layer, ids := range sn.Neighbors {
+			n.neighbors[layer] = make(map[string]*node, len(ids))
+			for _, id := range ids {
+				if nb, ok := g.nodes[id]; ok {
+					n.neighbors[layer][id] = nb
+				}
+			}
+		}
+	}
+
+	if s.EntryPoint != "" {
+		g.entryPoint = g.nodes[s.EntryPoint]
+	}
+	return g, nil
+}
@@ -2,8 +2,11 @@ package node
 
 import (
     "errors"
+    "sort"
     "sync"
 
+    "github.com/im-naren/savitar/pkg/filter"
+    "github.com/im-naren/savitar/pkg/hashring"
     "github.com/im-naren/savitar/pkg/shard"
     "github.com/im-naren/savitar/pkg/vector"
 )
@@ -12,18 +15,29 @@ import (
 type Node struct {
     ID     string
     shards map[string]*shard.Shard
+    ring   *hashring.Ring
     mu     sync.RWMutex
 }
 
-// NewNode initializes a new node with one shard
+// NewNode initializes a new node with one shard using the default shard
+// configuration (cosine metric, full precision).
 func NewNode(id string) *Node {
+    return NewNodeWithShard(id, shard.NewShard())
+}
+
+// NewNodeWithShard initializes a new node with one shard, s, letting a
+// caller pick a non-default metric or quantization (see
+// shard.NewShardWithMetric/NewQuantizedShard/NewScalarQuantizedShard)
+// instead of NewNode's cosine, full-precision default.
+func NewNodeWithShard(id string, s *shard.Shard) *Node {
     n := &Node{
         ID:     id,
         shards: make(map[string]*shard.Shard),
+        ring:   hashring.New(0),
     }
 
     // Add an initial shard to the node (simplified for demo purposes)
-    n.AddShard("default-shard", shard.NewShard())
+    n.AddShard("default-shard", s)
 
     return n
 }
@@ -33,6 +47,7 @@ func (n *Node) AddShard(shardID string, s *shard.Shard) {
     n.mu.Lock()
     defer n.mu.Unlock()
     n.shards[shardID] = s
+    n.ring.AddNode(shardID)
 }
 
 // AddVector adds a vector to the appropriate shard
@@ -53,16 +68,100 @@ func (n *Node) GetVector(id string) (vector.Vector, error) {
     return shard.GetVector(id)
 }
 
-// selectShard selects a shard based on the vector ID (simplified for demo purposes)
+// UpdateVector updates a vector in the appropriate shard
+func (n *Node) UpdateVector(v vector.Vector) error {
+    shard := n.selectShard(v.ID)
+    if shard == nil {
+        return errors.New("no shard found")
+    }
+    return shard.UpdateVector(v)
+}
+
+// selectShard picks the shard owning id via the node's consistent hash
+// ring over its shard IDs, rather than always returning the same shard.
 func (n *Node) selectShard(id string) *shard.Shard {
     n.mu.RLock()
     defer n.mu.RUnlock()
 
-    // Always return the default shard for now
-    if len(n.shards) > 0 {
-        for _, s := range n.shards {
-            return s
-        }
+    shardID, ok := n.ring.Get(id)
+    if !ok {
+        return nil
+    }
+    return n.shards[shardID]
+}
+
+// DeleteVector removes a vector from the appropriate shard
+func (n *Node) DeleteVector(id string) error {
+    shard := n.selectShard(id)
+    if shard == nil {
+        return errors.New("no shard found")
+    }
+    return shard.DeleteVector(id)
+}
+
+// AllVectors returns every vector stored across this node's shards, for use
+// by cluster-level rebalancing.
+func (n *Node) AllVectors() []vector.Vector {
+    n.mu.RLock()
+    shards := make([]*shard.Shard, 0, len(n.shards))
+    for _, s := range n.shards {
+        shards = append(shards, s)
     }
-    return nil
-}
\ No newline at end of file
+    n.mu.RUnlock()
+
+    var vectors []vector.Vector
+    for _, s := range shards {
+        vectors = append(vectors, s.AllVectors()...)
+    }
+    return vectors
+}
+
+// SearchVectors queries every shard on this node concurrently and merges
+// the results, returning the topN closest overall that match pred (a nil
+// pred matches everything).
+func (n *Node) SearchVectors(query []float64, topN, ef int, pred filter.Predicate) ([]shard.SearchResult, error) {
+    n.mu.RLock()
+    shards := make([]*shard.Shard, 0, len(n.shards))
+    for _, s := range n.shards {
+        shards = append(shards, s)
+    }
+    n.mu.RUnlock()
+
+    resultsChan := make(chan []shard.SearchResult, len(shards))
+    errChan := make(chan error, len(shards))
+    var wg sync.WaitGroup
+
+    for _, s := range shards {
+        wg.Add(1)
+        go func(s *shard.Shard) {
+            defer wg.Done()
+            hits, err := s.Search(query, topN, ef, pred)
+            if err != nil {
+                errChan <- err
+                return
+            }
+            resultsChan <- hits
+        }(s)
+    }
+
+    wg.Wait()
+    close(resultsChan)
+    close(errChan)
+
+    if len(errChan) > 0 {
+        return nil, <-errChan
+    }
+
+    var merged []shard.SearchResult
+    for hits := range resultsChan {
+        merged = append(merged, hits...)
+    }
+
+    sort.Slice(merged, func(i, j int) bool {
+        return merged[i].Distance < merged[j].Distance
+    })
+    if len(merged) > topN {
+        merged = merged[:topN]
+    }
+    return merged, nil
+}
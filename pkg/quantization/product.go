@@ -0,0 +1,176 @@
+// Package quantization trades vector precision for storage: scalar
+// quantization collapses each dimension to a single byte, and product
+// quantization collapses whole subvectors to one byte via a trained
+// codebook.
+package quantization
+
+import (
+	"errors"
+	"math"
+)
+
+// centroidsPerSubspace is fixed at 256 so each subvector's code fits in a
+// single byte.
+const centroidsPerSubspace = 256
+
+// kmeansIterations bounds how many Lloyd's-algorithm passes ProductQuantizer
+// training runs per subspace.
+const kmeansIterations = 25
+
+// ProductQuantizer splits a D-dimensional vector into m equal subvectors and
+// encodes each one as the index of its nearest of 256 centroids (one byte),
+// trading exact distances for an 8*D/m compression ratio versus float64.
+type ProductQuantizer struct {
+	m         int
+	subDim    int
+	centroids [][][]float64 // centroids[subspace][centroidIdx] -> subvector
+}
+
+// NewProductQuantizer trains a quantizer on vectors by running k-means with
+// 256 centroids independently within each of m subspaces. len(vectors[0])
+// must be a multiple of m.
+func NewProductQuantizer(vectors [][]float64, m int) (*ProductQuantizer, error) {
+	if len(vectors) == 0 {
+		return nil, errors.New("quantization: no training vectors")
+	}
+	if m <= 0 {
+		return nil, errors.New("quantization: m must be positive")
+	}
+	dim := len(vectors[0])
+	if dim%m != 0 {
+		return nil, errors.New("quantization: vector dimension must be a multiple of m")
+	}
+	subDim := dim / m
+
+	pq := &ProductQuantizer{m: m, subDim: subDim, centroids: make([][][]float64, m)}
+	for sub := 0; sub < m; sub++ {
+		subvectors := make([][]float64, len(vectors))
+		for i, v := range vectors {
+			subvectors[i] = v[sub*subDim : (sub+1)*subDim]
+		}
+		pq.centroids[sub] = kmeans(subvectors, centroidsPerSubspace)
+	}
+	return pq, nil
+}
+
+// Encode maps v to one byte per subspace: the index of its nearest centroid.
+func (pq *ProductQuantizer) Encode(v []float64) ([]byte, error) {
+	if len(v) != pq.m*pq.subDim {
+		return nil, errors.New("quantization: dimension mismatch")
+	}
+	codes := make([]byte, pq.m)
+	for sub := 0; sub < pq.m; sub++ {
+		subvec := v[sub*pq.subDim : (sub+1)*pq.subDim]
+		codes[sub] = byte(nearestCentroid(subvec, pq.centroids[sub]))
+	}
+	return codes, nil
+}
+
+// Decode reconstructs an approximation of the original vector by
+// concatenating each subspace's assigned centroid.
+func (pq *ProductQuantizer) Decode(codes []byte) []float64 {
+	v := make([]float64, 0, pq.m*pq.subDim)
+	for sub, c := range codes {
+		v = append(v, pq.centroids[sub][c]...)
+	}
+	return v
+}
+
+// DistanceTable is a precomputed per-subspace lookup from centroid index to
+// squared L2 distance from one fixed query vector, the asymmetric distance
+// computation from the product quantization literature: it lets Distance
+// look up rather than recompute a distance for every comparison.
+type DistanceTable [][]float64
+
+// DistanceTable precomputes the distance from query's subvectors to every
+// centroid in the corresponding subspace.
+func (pq *ProductQuantizer) DistanceTable(query []float64) (DistanceTable, error) {
+	if len(query) != pq.m*pq.subDim {
+		return nil, errors.New("quantization: dimension mismatch")
+	}
+	table := make(DistanceTable, pq.m)
+	for sub := 0; sub < pq.m; sub++ {
+		subvec := query[sub*pq.subDim : (sub+1)*pq.subDim]
+		row := make([]float64, len(pq.centroids[sub]))
+		for c, centroid := range pq.centroids[sub] {
+			row[c] = squaredL2(subvec, centroid)
+		}
+		table[sub] = row
+	}
+	return table, nil
+}
+
+// Distance approximates the squared L2 distance between the table's query
+// and the vector encoded as code, by summing precomputed per-subspace
+// centroid distances.
+func (table DistanceTable) Distance(code []byte) float64 {
+	var sum float64
+	for sub, c := range code {
+		sum += table[sub][c]
+	}
+	return sum
+}
+
+func squaredL2(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+func nearestCentroid(v []float64, centroids [][]float64) int {
+	best, bestDist := 0, math.Inf(1)
+	for i, c := range centroids {
+		d := squaredL2(v, c)
+		if d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// kmeans runs Lloyd's algorithm over vectors for a fixed number of
+// iterations, returning k centroids. Centroids are seeded from the training
+// vectors themselves (reused with wraparound if there are fewer than k), and
+// a centroid that attracts no points keeps its previous position rather
+// than going undefined.
+func kmeans(vectors [][]float64, k int) [][]float64 {
+	dim := len(vectors[0])
+
+	centroids := make([][]float64, k)
+	for i := range centroids {
+		centroids[i] = append([]float64(nil), vectors[i%len(vectors)]...)
+	}
+
+	assignments := make([]int, len(vectors))
+	for iter := 0; iter < kmeansIterations; iter++ {
+		for i, v := range vectors {
+			assignments[i] = nearestCentroid(v, centroids)
+		}
+
+		sums := make([][]float64, k)
+		counts := make([]int, k)
+		for i := range sums {
+			sums[i] = make([]float64, dim)
+		}
+		for i, v := range vectors {
+			c := assignments[i]
+			counts[c]++
+			for d := 0; d < dim; d++ {
+				sums[c][d] += v[d]
+			}
+		}
+
+		for c := 0; c < k; c++ {
+			if counts[c] == 0 {
+				continue
+			}
+			for d := 0; d < dim; d++ {
+				centroids[c][d] = sums[c][d] / float64(counts[c])
+			}
+		}
+	}
+	return centroids
+}
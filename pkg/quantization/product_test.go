@@ -0,0 +1,134 @@
+package quantization
+
+import (
+	"math"
+	"testing"
+)
+
+func clusteredVectors() [][]float64 {
+	// Two well-separated clusters in 4 dimensions so k-means and nearest-
+	// centroid lookups have an unambiguous right answer to check against.
+	var vectors [][]float64
+	for i := 0; i < 50; i++ {
+		vectors = append(vectors, []float64{0, 0, 0, 0})
+		vectors = append(vectors, []float64{10, 10, 10, 10})
+	}
+	return vectors
+}
+
+func TestNewProductQuantizerValidatesInput(t *testing.T) {
+	if _, err := NewProductQuantizer(nil, 2); err == nil {
+		t.Error("expected an error for no training vectors")
+	}
+	vectors := [][]float64{{1, 2, 3, 4}}
+	if _, err := NewProductQuantizer(vectors, 0); err == nil {
+		t.Error("expected an error for m <= 0")
+	}
+	if _, err := NewProductQuantizer(vectors, 3); err == nil {
+		t.Error("expected an error when dimension is not a multiple of m")
+	}
+}
+
+func TestProductQuantizerEncodeDecodeRoundTrip(t *testing.T) {
+	pq, err := NewProductQuantizer(clusteredVectors(), 2)
+	if err != nil {
+		t.Fatalf("NewProductQuantizer: %v", err)
+	}
+
+	codes, err := pq.Encode([]float64{0, 0, 0, 0})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	decoded := pq.Decode(codes)
+	for d, v := range decoded {
+		if math.Abs(v) > 1e-6 {
+			t.Errorf("decoded[%d] = %v, want ~0", d, v)
+		}
+	}
+
+	codes, err = pq.Encode([]float64{10, 10, 10, 10})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	decoded = pq.Decode(codes)
+	for d, v := range decoded {
+		if math.Abs(v-10) > 1e-6 {
+			t.Errorf("decoded[%d] = %v, want ~10", d, v)
+		}
+	}
+}
+
+func TestProductQuantizerEncodeDimensionMismatch(t *testing.T) {
+	pq, err := NewProductQuantizer(clusteredVectors(), 2)
+	if err != nil {
+		t.Fatalf("NewProductQuantizer: %v", err)
+	}
+	if _, err := pq.Encode([]float64{1, 2}); err == nil {
+		t.Error("expected a dimension mismatch error")
+	}
+}
+
+func TestDistanceTableMatchesDirectDistance(t *testing.T) {
+	pq, err := NewProductQuantizer(clusteredVectors(), 2)
+	if err != nil {
+		t.Fatalf("NewProductQuantizer: %v", err)
+	}
+
+	query := []float64{0, 0, 0, 0}
+	table, err := pq.DistanceTable(query)
+	if err != nil {
+		t.Fatalf("DistanceTable: %v", err)
+	}
+
+	codes, err := pq.Encode([]float64{10, 10, 10, 10})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	decoded := pq.Decode(codes)
+
+	approx := table.Distance(codes)
+	exact := squaredL2(query, decoded)
+	if math.Abs(approx-exact) > 1e-6 {
+		t.Errorf("table.Distance() = %v, want %v (exact distance to the decoded centroid)", approx, exact)
+	}
+}
+
+func TestDistanceTableDimensionMismatch(t *testing.T) {
+	pq, err := NewProductQuantizer(clusteredVectors(), 2)
+	if err != nil {
+		t.Fatalf("NewProductQuantizer: %v", err)
+	}
+	if _, err := pq.DistanceTable([]float64{1, 2}); err == nil {
+		t.Error("expected a dimension mismatch error")
+	}
+}
+
+func TestNearestCentroid(t *testing.T) {
+	centroids := [][]float64{{0, 0}, {10, 10}}
+	if got := nearestCentroid([]float64{1, 1}, centroids); got != 0 {
+		t.Errorf("nearestCentroid() = %d, want 0", got)
+	}
+	if got := nearestCentroid([]float64{9, 9}, centroids); got != 1 {
+		t.Errorf("nearestCentroid() = %d, want 1", got)
+	}
+}
+
+func TestKmeansFindsSeparatedClusters(t *testing.T) {
+	centroids := kmeans(clusteredVectors(), 2)
+	if len(centroids) != 2 {
+		t.Fatalf("kmeans returned %d centroids, want 2", len(centroids))
+	}
+
+	// One centroid should land near each cluster, in either order.
+	near := func(c []float64, want float64) bool {
+		for _, v := range c {
+			if math.Abs(v-want) > 1e-6 {
+				return false
+			}
+		}
+		return true
+	}
+	if !((near(centroids[0], 0) && near(centroids[1], 10)) || (near(centroids[0], 10) && near(centroids[1], 0))) {
+		t.Errorf("kmeans centroids = %v, want one near all-0s and one near all-10s", centroids)
+	}
+}
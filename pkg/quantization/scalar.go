@@ -0,0 +1,71 @@
+package quantization
+
+import "errors"
+
+// ScalarQuantizer maps float64 vectors to int8 codes using a per-dimension
+// affine scale fitted from training data, an 8x reduction in storage versus
+// float64 at the cost of per-dimension precision.
+type ScalarQuantizer struct {
+	min   []float64
+	scale []float64 // (max-min)/255 per dimension
+}
+
+// NewScalarQuantizer fits a quantizer to vectors, which must all share the
+// same dimensionality.
+func NewScalarQuantizer(vectors [][]float64) (*ScalarQuantizer, error) {
+	if len(vectors) == 0 {
+		return nil, errors.New("quantization: no training vectors")
+	}
+	dim := len(vectors[0])
+
+	min := append([]float64(nil), vectors[0]...)
+	max := append([]float64(nil), vectors[0]...)
+	for _, v := range vectors {
+		for d := 0; d < dim; d++ {
+			if v[d] < min[d] {
+				min[d] = v[d]
+			}
+			if v[d] > max[d] {
+				max[d] = v[d]
+			}
+		}
+	}
+
+	scale := make([]float64, dim)
+	for d := 0; d < dim; d++ {
+		span := max[d] - min[d]
+		if span == 0 {
+			span = 1
+		}
+		scale[d] = span / 255
+	}
+	return &ScalarQuantizer{min: min, scale: scale}, nil
+}
+
+// Encode quantizes v to one int8 per dimension.
+func (q *ScalarQuantizer) Encode(v []float64) ([]int8, error) {
+	if len(v) != len(q.min) {
+		return nil, errors.New("quantization: dimension mismatch")
+	}
+	codes := make([]int8, len(v))
+	for d, val := range v {
+		normalized := (val - q.min[d]) / q.scale[d]
+		switch {
+		case normalized < 0:
+			normalized = 0
+		case normalized > 255:
+			normalized = 255
+		}
+		codes[d] = int8(int(normalized) - 128)
+	}
+	return codes, nil
+}
+
+// Decode reconstructs an approximation of the original vector from codes.
+func (q *ScalarQuantizer) Decode(codes []int8) []float64 {
+	v := make([]float64, len(codes))
+	for d, c := range codes {
+		v[d] = q.min[d] + float64(int(c)+128)*q.scale[d]
+	}
+	return v
+}
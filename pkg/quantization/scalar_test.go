@@ -0,0 +1,86 @@
+package quantization
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewScalarQuantizerValidatesInput(t *testing.T) {
+	if _, err := NewScalarQuantizer(nil); err == nil {
+		t.Error("expected an error for no training vectors")
+	}
+}
+
+func TestScalarQuantizerEncodeDecodeRoundTrip(t *testing.T) {
+	training := [][]float64{{0, 0}, {10, 20}, {5, 10}}
+	q, err := NewScalarQuantizer(training)
+	if err != nil {
+		t.Fatalf("NewScalarQuantizer: %v", err)
+	}
+
+	for _, v := range training {
+		codes, err := q.Encode(v)
+		if err != nil {
+			t.Fatalf("Encode(%v): %v", v, err)
+		}
+		decoded := q.Decode(codes)
+		for d := range v {
+			// One int8 per dimension is lossy; the affine scale guarantees
+			// the decoded value stays within one quantization step of the
+			// original.
+			if math.Abs(decoded[d]-v[d]) > 0.2 {
+				t.Errorf("Decode(Encode(%v))[%d] = %v, want ~%v", v, d, decoded[d], v[d])
+			}
+		}
+	}
+}
+
+func TestScalarQuantizerEncodeDimensionMismatch(t *testing.T) {
+	q, err := NewScalarQuantizer([][]float64{{0, 0}, {10, 10}})
+	if err != nil {
+		t.Fatalf("NewScalarQuantizer: %v", err)
+	}
+	if _, err := q.Encode([]float64{1, 2, 3}); err == nil {
+		t.Error("expected a dimension mismatch error")
+	}
+}
+
+func TestScalarQuantizerClampsOutOfRangeValues(t *testing.T) {
+	q, err := NewScalarQuantizer([][]float64{{0}, {10}})
+	if err != nil {
+		t.Fatalf("NewScalarQuantizer: %v", err)
+	}
+
+	low, err := q.Encode([]float64{-100})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if got := q.Decode(low)[0]; got < -1e-6 {
+		t.Errorf("Decode(Encode(-100))[0] = %v, want clamped to ~0", got)
+	}
+
+	high, err := q.Encode([]float64{1000})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if got := q.Decode(high)[0]; got > 10+1e-6 {
+		t.Errorf("Decode(Encode(1000))[0] = %v, want clamped to ~10", got)
+	}
+}
+
+func TestScalarQuantizerConstantDimension(t *testing.T) {
+	// All training vectors agree on dimension 0; span is zero there, which
+	// must not divide by zero when computing the per-dimension scale.
+	q, err := NewScalarQuantizer([][]float64{{5, 0}, {5, 10}})
+	if err != nil {
+		t.Fatalf("NewScalarQuantizer: %v", err)
+	}
+	codes, err := q.Encode([]float64{5, 5})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	decoded := q.Decode(codes)
+	if math.Abs(decoded[0]-5) > 1e-6 {
+		t.Errorf("decoded[0] = %v, want ~5", decoded[0])
+	}
+}
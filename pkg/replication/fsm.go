@@ -0,0 +1,90 @@
+// Package replication wraps a shard.Shard in a Raft state machine so its
+// mutations are replicated across a configurable number of peers instead of
+// applying locally.
+package replication
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/im-naren/savitar/pkg/shard"
+	"github.com/im-naren/savitar/pkg/vector"
+)
+
+// opKind identifies which shard mutation a replicated log entry applies.
+type opKind string
+
+const (
+	opAdd    opKind = "add"
+	opUpdate opKind = "update"
+	opDelete opKind = "delete"
+)
+
+// command is the payload appended to the Raft log for every shard mutation.
+type command struct {
+	Op     opKind        `json:"op"`
+	Vector vector.Vector `json:"vector,omitempty"`
+	ID     string        `json:"id,omitempty"`
+}
+
+// fsm applies replicated commands to a local shard.Shard.
+type fsm struct {
+	shard *shard.Shard
+}
+
+// Apply is called once a command has committed to a majority of the group
+// and is safe to apply locally.
+func (f *fsm) Apply(l *raft.Log) interface{} {
+	var cmd command
+	if err := json.Unmarshal(l.Data, &cmd); err != nil {
+		return fmt.Errorf("replication: invalid log entry: %w", err)
+	}
+
+	switch cmd.Op {
+	case opAdd:
+		return f.shard.AddVector(cmd.Vector)
+	case opUpdate:
+		return f.shard.UpdateVector(cmd.Vector)
+	case opDelete:
+		return f.shard.DeleteVector(cmd.ID)
+	default:
+		return fmt.Errorf("replication: unknown op %q", cmd.Op)
+	}
+}
+
+// fsmSnapshot is a point-in-time copy of the shard's vectors, persisted so
+// Raft can truncate its log instead of replaying full history on recovery.
+type fsmSnapshot struct {
+	vectors []vector.Vector
+}
+
+// Snapshot captures the shard's current contents.
+func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
+	return &fsmSnapshot{vectors: f.shard.AllVectors()}, nil
+}
+
+// Persist writes the snapshot to sink.
+func (s *fsmSnapshot) Persist(sink raft.SnapshotSink) error {
+	if err := json.NewEncoder(sink).Encode(s.vectors); err != nil {
+		sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+// Release is a no-op; the snapshot holds no external resources.
+func (s *fsmSnapshot) Release() {}
+
+// Restore replaces the shard's contents with a previously persisted
+// snapshot, letting a node recover without replaying the full log.
+func (f *fsm) Restore(rc io.ReadCloser) error {
+	defer rc.Close()
+	var vectors []vector.Vector
+	if err := json.NewDecoder(rc).Decode(&vectors); err != nil {
+		return err
+	}
+	return f.shard.Restore(vectors)
+}
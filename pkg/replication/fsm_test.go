@@ -0,0 +1,105 @@
+package replication
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/im-naren/savitar/pkg/shard"
+	"github.com/im-naren/savitar/pkg/vector"
+)
+
+func applyCommand(t *testing.T, f *fsm, cmd command) interface{} {
+	t.Helper()
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		t.Fatalf("marshaling command: %v", err)
+	}
+	return f.Apply(&raft.Log{Data: data})
+}
+
+func TestFSMApplyAddUpdateDelete(t *testing.T) {
+	f := &fsm{shard: shard.NewShard()}
+
+	if err := applyCommand(t, f, command{Op: opAdd, Vector: vector.Vector{ID: "a", Data: []float64{1, 2}}}); err != nil {
+		t.Fatalf("Apply(add): %v", err)
+	}
+	if _, err := f.shard.GetVector("a"); err != nil {
+		t.Fatalf("GetVector after add: %v", err)
+	}
+
+	if err := applyCommand(t, f, command{Op: opUpdate, Vector: vector.Vector{ID: "a", Data: []float64{3, 4}}}); err != nil {
+		t.Fatalf("Apply(update): %v", err)
+	}
+	got, err := f.shard.GetVector("a")
+	if err != nil {
+		t.Fatalf("GetVector after update: %v", err)
+	}
+	if got.Data[0] != 3 {
+		t.Fatalf("GetVector() after update = %v, want Data[0] == 3", got)
+	}
+
+	if err := applyCommand(t, f, command{Op: opDelete, ID: "a"}); err != nil {
+		t.Fatalf("Apply(delete): %v", err)
+	}
+	if _, err := f.shard.GetVector("a"); err == nil {
+		t.Fatal("expected an error getting a deleted vector")
+	}
+}
+
+func TestFSMApplyUnknownOp(t *testing.T) {
+	f := &fsm{shard: shard.NewShard()}
+	err, _ := applyCommand(t, f, command{Op: "bogus"}).(error)
+	if err == nil {
+		t.Fatal("expected an error for an unknown op")
+	}
+}
+
+func TestFSMApplyInvalidLogEntry(t *testing.T) {
+	f := &fsm{shard: shard.NewShard()}
+	err, _ := f.Apply(&raft.Log{Data: []byte("not json")}).(error)
+	if err == nil {
+		t.Fatal("expected an error for an invalid log entry")
+	}
+}
+
+func TestFSMSnapshotRestoreRoundTrip(t *testing.T) {
+	f := &fsm{shard: shard.NewShard()}
+	if err := f.shard.AddVector(vector.Vector{ID: "a", Data: []float64{1, 2}}); err != nil {
+		t.Fatalf("AddVector: %v", err)
+	}
+	if err := f.shard.AddVector(vector.Vector{ID: "b", Data: []float64{3, 4}}); err != nil {
+		t.Fatalf("AddVector: %v", err)
+	}
+
+	snap, err := f.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	store := raft.NewInmemSnapshotStore()
+	sink, err := store.Create(1, 0, 0, raft.Configuration{}, 0, nil)
+	if err != nil {
+		t.Fatalf("store.Create: %v", err)
+	}
+	if err := snap.Persist(sink); err != nil {
+		t.Fatalf("Persist: %v", err)
+	}
+
+	_, rc, err := store.Open(sink.ID())
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+
+	restored := &fsm{shard: shard.NewShard()}
+	if err := restored.Restore(rc); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	for _, id := range []string{"a", "b"} {
+		if _, err := restored.shard.GetVector(id); err != nil {
+			t.Errorf("GetVector(%s) after restore: %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package replication
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/im-naren/savitar/pkg/filter"
+	"github.com/im-naren/savitar/pkg/shard"
+	"github.com/im-naren/savitar/pkg/vector"
+)
+
+// applyTimeout bounds how long a write waits for the Raft group to commit.
+const applyTimeout = 5 * time.Second
+
+// ErrNotLeader is returned by a write when this replica is not the leader of
+// its group. Leader names the peer the caller should forward the write to
+// instead, the same follower-to-leader forwarding used by simple
+// Raft-backed KV servers.
+type ErrNotLeader struct {
+	Leader raft.ServerAddress
+}
+
+func (e *ErrNotLeader) Error() string {
+	if e.Leader == "" {
+		return "replication: no leader elected"
+	}
+	return "replication: not leader, forward to " + string(e.Leader)
+}
+
+// Config describes how to stand up a ReplicatedShard.
+type Config struct {
+	LocalID   raft.ServerID
+	Bootstrap bool // true for exactly one peer, the one that forms the group
+	Servers   []raft.Server
+	Transport raft.Transport
+}
+
+// ReplicatedShard wraps a shard.Shard in a Raft group so AddVector,
+// UpdateVector and DeleteVector become replicated log entries applied to
+// every member of the group instead of local mutations.
+type ReplicatedShard struct {
+	shard *shard.Shard
+	raft  *raft.Raft
+	fsm   *fsm
+}
+
+// NewReplicatedShard starts the Raft group backing s.
+func NewReplicatedShard(s *shard.Shard, cfg Config) (*ReplicatedShard, error) {
+	f := &fsm{shard: s}
+
+	raftCfg := raft.DefaultConfig()
+	raftCfg.LocalID = cfg.LocalID
+
+	logStore := raft.NewInmemStore()
+	stableStore := raft.NewInmemStore()
+	snapshotStore := raft.NewInmemSnapshotStore()
+
+	r, err := raft.NewRaft(raftCfg, f, logStore, stableStore, snapshotStore, cfg.Transport)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Bootstrap {
+		future := r.BootstrapCluster(raft.Configuration{Servers: cfg.Servers})
+		if err := future.Error(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ReplicatedShard{shard: s, raft: r, fsm: f}, nil
+}
+
+func (rs *ReplicatedShard) apply(cmd command) error {
+	if rs.raft.State() != raft.Leader {
+		return &ErrNotLeader{Leader: rs.raft.Leader()}
+	}
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+	future := rs.raft.Apply(data, applyTimeout)
+	if err := future.Error(); err != nil {
+		return err
+	}
+	if err, ok := future.Response().(error); ok && err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddVector replicates an insert to every member of the shard's group.
+// It must be called on the current leader; see IsLeader and Leader.
+func (rs *ReplicatedShard) AddVector(v vector.Vector) error {
+	return rs.apply(command{Op: opAdd, Vector: v})
+}
+
+// UpdateVector replicates an update to every member of the shard's group.
+func (rs *ReplicatedShard) UpdateVector(v vector.Vector) error {
+	return rs.apply(command{Op: opUpdate, Vector: v})
+}
+
+// DeleteVector replicates a delete to every member of the shard's group.
+func (rs *ReplicatedShard) DeleteVector(id string) error {
+	return rs.apply(command{Op: opDelete, ID: id})
+}
+
+// GetVector reads from the local replica. Callers that need a linearizable
+// read must only call this when IsLeader is true; stale-ok reads may call it
+// on any replica.
+func (rs *ReplicatedShard) GetVector(id string) (vector.Vector, error) {
+	return rs.shard.GetVector(id)
+}
+
+// Search runs the shard's HNSW search against the local replica.
+func (rs *ReplicatedShard) Search(query []float64, topN, ef int, pred filter.Predicate) ([]shard.SearchResult, error) {
+	return rs.shard.Search(query, topN, ef, pred)
+}
+
+// IsLeader reports whether this replica currently holds the group's Raft
+// leadership.
+func (rs *ReplicatedShard) IsLeader() bool {
+	return rs.raft.State() == raft.Leader
+}
+
+// Leader returns the address of the group's current leader, if known.
+func (rs *ReplicatedShard) Leader() raft.ServerAddress {
+	return rs.raft.Leader()
+}
+
+// Shutdown stops the Raft group for this replica.
+func (rs *ReplicatedShard) Shutdown() error {
+	return rs.raft.Shutdown().Error()
+}
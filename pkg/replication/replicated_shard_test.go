@@ -0,0 +1,111 @@
+package replication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/im-naren/savitar/pkg/shard"
+	"github.com/im-naren/savitar/pkg/vector"
+)
+
+// newTestReplicatedShard stands up a single-node Raft group (bootstrap =
+// true), which is enough to exercise ReplicatedShard's write/read path
+// without standing up a multi-peer cluster in tests.
+func newTestReplicatedShard(t *testing.T) *ReplicatedShard {
+	t.Helper()
+	id := raft.ServerID("node-0")
+	addr, transport := raft.NewInmemTransport(raft.ServerAddress(id))
+
+	rs, err := NewReplicatedShard(shard.NewShard(), Config{
+		LocalID:   id,
+		Bootstrap: true,
+		Servers:   []raft.Server{{ID: id, Address: addr}},
+		Transport: transport,
+	})
+	if err != nil {
+		t.Fatalf("NewReplicatedShard: %v", err)
+	}
+	t.Cleanup(func() { rs.Shutdown() })
+
+	waitForLeader(t, rs)
+	return rs
+}
+
+func waitForLeader(t *testing.T, rs *ReplicatedShard) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if rs.IsLeader() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for this single-node group to become leader")
+}
+
+func TestReplicatedShardAddGetDeleteVector(t *testing.T) {
+	rs := newTestReplicatedShard(t)
+
+	if err := rs.AddVector(vector.Vector{ID: "a", Data: []float64{1, 2}}); err != nil {
+		t.Fatalf("AddVector: %v", err)
+	}
+	got, err := rs.GetVector("a")
+	if err != nil {
+		t.Fatalf("GetVector: %v", err)
+	}
+	if got.ID != "a" {
+		t.Fatalf("GetVector() = %+v, want ID \"a\"", got)
+	}
+
+	if err := rs.UpdateVector(vector.Vector{ID: "a", Data: []float64{5, 6}}); err != nil {
+		t.Fatalf("UpdateVector: %v", err)
+	}
+	got, err = rs.GetVector("a")
+	if err != nil {
+		t.Fatalf("GetVector after update: %v", err)
+	}
+	if got.Data[0] != 5 {
+		t.Fatalf("GetVector() after update = %+v, want Data[0] == 5", got)
+	}
+
+	if err := rs.DeleteVector("a"); err != nil {
+		t.Fatalf("DeleteVector: %v", err)
+	}
+	if _, err := rs.GetVector("a"); err == nil {
+		t.Fatal("expected an error getting a deleted vector")
+	}
+}
+
+func TestReplicatedShardSearch(t *testing.T) {
+	rs := newTestReplicatedShard(t)
+	for _, v := range []vector.Vector{
+		{ID: "near", Data: []float64{1, 0}},
+		{ID: "far", Data: []float64{100, 100}},
+	} {
+		if err := rs.AddVector(v); err != nil {
+			t.Fatalf("AddVector(%s): %v", v.ID, err)
+		}
+	}
+
+	results, err := rs.Search([]float64{2, 0}, 1, 10, nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].Vector.ID != "near" {
+		t.Fatalf("Search() = %v, want [near]", results)
+	}
+}
+
+func TestErrNotLeaderMessage(t *testing.T) {
+	err := &ErrNotLeader{}
+	if err.Error() == "" {
+		t.Fatal("expected a non-empty message with no leader elected")
+	}
+
+	err = &ErrNotLeader{Leader: "node-1"}
+	if err.Error() != "replication: not leader, forward to node-1" {
+		t.Fatalf("Error() = %q, want a message naming the leader", err.Error())
+	}
+}
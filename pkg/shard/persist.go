@@ -0,0 +1,85 @@
+package shard
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"os"
+
+	"github.com/im-naren/savitar/pkg/index/hnsw"
+	"github.com/im-naren/savitar/pkg/vector"
+)
+
+// shardSnapshot is the on-disk format NewShardAtPath/Close use to skip
+// re-indexing every vector from scratch on restart. It wraps the HNSW
+// graph's own Snapshot, which stores edges and levels but not metadata,
+// alongside each vector in full (including Meta) so filters keep working
+// after a restore.
+type shardSnapshot struct {
+	Graph   []byte
+	Vectors []vector.Vector
+}
+
+// NewShardAtPath is like NewShardWithMetric, but persists its HNSW index to
+// path on Close and restores it from there on startup instead of starting
+// empty, so a process restart doesn't need to re-index every vector. A
+// missing file is not an error; the shard just starts empty, as on first
+// run.
+func NewShardAtPath(path string, metric vector.Metric) (*Shard, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		s := NewShardWithMetric(metric)
+		s.path = path
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("shard: reading snapshot %s: %w", path, err)
+	}
+
+	var snap shardSnapshot
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("shard: decoding snapshot %s: %w", path, err)
+	}
+	graph, err := hnsw.LoadGraph(snap.Graph, hnsw.Config{Distance: metric.Distance})
+	if err != nil {
+		return nil, fmt.Errorf("shard: loading graph from %s: %w", path, err)
+	}
+
+	s := newShard(metric)
+	s.path = path
+	s.index = graph
+	for _, v := range snap.Vectors {
+		s.vectors[v.ID] = v
+	}
+	go s.runCompactionLoop()
+	return s, nil
+}
+
+// writeSnapshot serializes the shard's HNSW graph and vector data to path.
+// It is a no-op for a quantized shard, which has no HNSW graph to snapshot.
+func (s *Shard) writeSnapshot(path string) error {
+	s.mu.RLock()
+	if s.index == nil {
+		s.mu.RUnlock()
+		return nil
+	}
+	vectors := make([]vector.Vector, 0, len(s.vectors))
+	for _, v := range s.vectors {
+		vectors = append(vectors, v)
+	}
+	s.mu.RUnlock()
+
+	graphData, err := s.index.Snapshot()
+	if err != nil {
+		return fmt.Errorf("shard: snapshotting graph: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(shardSnapshot{Graph: graphData, Vectors: vectors}); err != nil {
+		return fmt.Errorf("shard: encoding snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("shard: writing snapshot %s: %w", path, err)
+	}
+	return nil
+}
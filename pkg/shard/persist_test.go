@@ -0,0 +1,73 @@
+package shard
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/im-naren/savitar/pkg/quantization"
+	"github.com/im-naren/savitar/pkg/vector"
+)
+
+func TestShardAtPathSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shard.gob")
+
+	s, err := NewShardAtPath(path, vector.L2Metric{})
+	if err != nil {
+		t.Fatalf("NewShardAtPath: %v", err)
+	}
+	if err := s.AddVector(vector.Vector{ID: "a", Data: []float64{0, 0}, Meta: map[string]any{"k": "v"}}); err != nil {
+		t.Fatalf("AddVector: %v", err)
+	}
+	if err := s.AddVector(vector.Vector{ID: "b", Data: []float64{10, 10}}); err != nil {
+		t.Fatalf("AddVector: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopened, err := NewShardAtPath(path, vector.L2Metric{})
+	if err != nil {
+		t.Fatalf("NewShardAtPath (reopen): %v", err)
+	}
+	defer reopened.Close()
+
+	results, err := reopened.Search([]float64{0, 0}, 1, 10, nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].Vector.ID != "a" {
+		t.Fatalf("Search() after reopen = %v, want [a]", results)
+	}
+	if got := results[0].Vector.Meta["k"]; got != "v" {
+		t.Errorf("Meta[\"k\"] = %v, want \"v\"", got)
+	}
+}
+
+func TestShardAtPathMissingFileStartsEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.gob")
+
+	s, err := NewShardAtPath(path, vector.CosineMetric{})
+	if err != nil {
+		t.Fatalf("NewShardAtPath: %v", err)
+	}
+	defer s.Close()
+
+	results, err := s.Search([]float64{1, 2}, 5, 10, nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("Search() on a fresh shard = %v, want none", results)
+	}
+}
+
+func TestQuantizedShardCloseHasNothingToSnapshot(t *testing.T) {
+	q, err := quantization.NewScalarQuantizer([][]float64{{0, 0}, {10, 10}})
+	if err != nil {
+		t.Fatalf("NewScalarQuantizer: %v", err)
+	}
+	s := NewScalarQuantizedShard(q, vector.L2Metric{}, true)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
@@ -2,25 +2,127 @@ package shard
 
 import (
 	"errors"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/im-naren/savitar/pkg/filter"
+	"github.com/im-naren/savitar/pkg/index/hnsw"
+	"github.com/im-naren/savitar/pkg/quantization"
 	"github.com/im-naren/savitar/pkg/vector"
 )
 
+// compactionInterval controls how often a shard sweeps tombstoned vectors
+// out of its HNSW graph.
+const compactionInterval = 30 * time.Second
+
+// quantizedRerankFactor controls how many extra candidates a quantized
+// shard pulls from the coarse code scan before reranking with the exact
+// metric (e.g. 10*topN candidates reranked down to topN).
+const quantizedRerankFactor = 10
+
+// SearchResult is a single hit returned by Shard.Search, pairing the stored
+// vector with its distance to the query.
+type SearchResult struct {
+	Vector   vector.Vector
+	Distance float64
+}
+
 // Shard stores a partition of the vector data and supports concurrent operations
 type Shard struct {
 	vectors map[string]vector.Vector
 	mu      sync.RWMutex
+
+	metric vector.Metric
+
+	// index serves Search when the shard holds full-precision vectors. It
+	// is nil for quantized shards, which search codes directly instead.
+	index *hnsw.Graph
+
+	// quantizer and codes serve Search when the shard was created with
+	// product quantization enabled; see NewQuantizedShard.
+	quantizer *quantization.ProductQuantizer
+	codes     map[string][]byte
+
+	// scalarQuantizer and scalarCodes serve Search when the shard was
+	// created with scalar quantization enabled; see NewScalarQuantizedShard.
+	// A shard uses at most one of quantizer or scalarQuantizer.
+	scalarQuantizer *quantization.ScalarQuantizer
+	scalarCodes     map[string][]int8
+
+	keepFullPrecision bool
+
+	// path, if non-empty, is where Close writes an HNSW snapshot so the
+	// shard doesn't have to re-index from scratch on the next NewShardAtPath
+	// call. Empty for shards that aren't persisted, including every
+	// quantized shard (quantization has no HNSW graph to snapshot).
+	path string
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
 }
 
-// NewShard initializes a new shard instance
+// NewShard initializes a new shard instance using cosine similarity, an
+// HNSW index over full-precision vectors, and a background goroutine that
+// periodically compacts deleted vectors.
 func NewShard() *Shard {
+	return NewShardWithMetric(vector.CosineMetric{})
+}
+
+// NewShardWithMetric is like NewShard but lets a collection configure which
+// Metric distances are computed with.
+func NewShardWithMetric(metric vector.Metric) *Shard {
+	s := newShard(metric)
+	s.index = hnsw.NewGraph(hnsw.Config{Distance: metric.Distance})
+	go s.runCompactionLoop()
+	return s
+}
+
+// NewQuantizedShard creates a shard that stores product-quantized codes
+// instead of an HNSW graph over full-precision vectors, trading recall for
+// an m-byte-per-vector footprint. Search scans codes using the quantizer's
+// precomputed distance table, then reranks the closest candidates with the
+// exact metric. When keepFullPrecision is false, the original vector data is
+// discarded: reranking and AllVectors fall back to the quantizer's
+// reconstruction, while GetVector returns the vector with nil Data.
+func NewQuantizedShard(quantizer *quantization.ProductQuantizer, metric vector.Metric, keepFullPrecision bool) *Shard {
+	s := newShard(metric)
+	s.quantizer = quantizer
+	s.codes = make(map[string][]byte)
+	s.keepFullPrecision = keepFullPrecision
+	go s.runCompactionLoop()
+	return s
+}
+
+// NewScalarQuantizedShard creates a shard that stores scalar-quantized codes
+// (one int8 per dimension) instead of an HNSW graph over full-precision
+// vectors. Unlike product quantization it has no precomputed distance
+// table, so Search decodes every candidate and reranks with the exact
+// metric directly rather than scanning approximate distances first. When
+// keepFullPrecision is false, the original vector data is discarded:
+// reranking and AllVectors fall back to the quantizer's reconstruction,
+// while GetVector returns the vector with nil Data.
+func NewScalarQuantizedShard(quantizer *quantization.ScalarQuantizer, metric vector.Metric, keepFullPrecision bool) *Shard {
+	s := newShard(metric)
+	s.scalarQuantizer = quantizer
+	s.scalarCodes = make(map[string][]int8)
+	s.keepFullPrecision = keepFullPrecision
+	go s.runCompactionLoop()
+	return s
+}
+
+func newShard(metric vector.Metric) *Shard {
+	if metric == nil {
+		metric = vector.CosineMetric{}
+	}
 	return &Shard{
 		vectors: make(map[string]vector.Vector),
+		metric:  metric,
+		stopCh:  make(chan struct{}),
 	}
 }
 
-// AddVector adds a vector to the shard
+// AddVector adds a vector to the shard and indexes it for approximate search
 func (s *Shard) AddVector(v vector.Vector) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -28,8 +130,7 @@ func (s *Shard) AddVector(v vector.Vector) error {
 	if _, exists := s.vectors[v.ID]; exists {
 		return errors.New("vector with this ID already exists")
 	}
-	s.vectors[v.ID] = v
-	return nil
+	return s.store(v)
 }
 
 // GetVector retrieves a vector by its ID
@@ -44,7 +145,9 @@ func (s *Shard) GetVector(id string) (vector.Vector, error) {
 	return v, nil
 }
 
-// DeleteVector removes a vector by ID
+// DeleteVector removes a vector by ID. On a full-precision shard it is
+// tombstoned in the HNSW index immediately and reclaimed by the next
+// background compaction; on a quantized shard its code is dropped directly.
 func (s *Shard) DeleteVector(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -53,5 +156,257 @@ func (s *Shard) DeleteVector(id string) error {
 		return errors.New("vector not found")
 	}
 	delete(s.vectors, id)
+	delete(s.codes, id)
+	delete(s.scalarCodes, id)
+	if s.index != nil {
+		return s.index.DeleteVector(id)
+	}
+	return nil
+}
+
+// UpdateVector replaces an existing vector's data/metadata in place,
+// re-indexing (or re-encoding) it so subsequent searches see the new data.
+func (s *Shard) UpdateVector(v vector.Vector) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.vectors[v.ID]; !exists {
+		return errors.New("vector not found")
+	}
+	return s.store(v)
+}
+
+// store writes v into the shard's vectors map and updates whichever search
+// structure is active. The caller must hold s.mu.
+func (s *Shard) store(v vector.Vector) error {
+	if s.quantizer != nil {
+		code, err := s.quantizer.Encode(v.Data)
+		if err != nil {
+			return err
+		}
+		s.codes[v.ID] = code
+		stored := v
+		if !s.keepFullPrecision {
+			stored.Data = nil
+		}
+		s.vectors[v.ID] = stored
+		return nil
+	}
+	if s.scalarQuantizer != nil {
+		code, err := s.scalarQuantizer.Encode(v.Data)
+		if err != nil {
+			return err
+		}
+		s.scalarCodes[v.ID] = code
+		stored := v
+		if !s.keepFullPrecision {
+			stored.Data = nil
+		}
+		s.vectors[v.ID] = stored
+		return nil
+	}
+
+	s.vectors[v.ID] = v
+	return s.index.AddVector(v.ID, v.Data)
+}
+
+// AllVectors returns every vector currently stored in the shard, for use by
+// snapshotting.
+func (s *Shard) AllVectors() []vector.Vector {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	vectors := make([]vector.Vector, 0, len(s.vectors))
+	for id, v := range s.vectors {
+		if v.Data == nil && s.quantizer != nil {
+			v.Data = s.quantizer.Decode(s.codes[id])
+		}
+		if v.Data == nil && s.scalarQuantizer != nil {
+			v.Data = s.scalarQuantizer.Decode(s.scalarCodes[id])
+		}
+		vectors = append(vectors, v)
+	}
+	return vectors
+}
+
+// Restore replaces the shard's contents with vectors, rebuilding whichever
+// search structure is active from scratch. It is used to apply a
+// replication snapshot.
+func (s *Shard) Restore(vectors []vector.Vector) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.vectors = make(map[string]vector.Vector, len(vectors))
+	switch {
+	case s.quantizer != nil:
+		s.codes = make(map[string][]byte, len(vectors))
+	case s.scalarQuantizer != nil:
+		s.scalarCodes = make(map[string][]int8, len(vectors))
+	default:
+		s.index = hnsw.NewGraph(hnsw.Config{Distance: s.metric.Distance})
+	}
+	for _, v := range vectors {
+		if err := s.store(v); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// Search returns up to topN nearest neighbors of query matching pred (a nil
+// pred matches everything). On a full-precision shard this explores an
+// ef-sized candidate list in the HNSW graph (ef should be >= topN) and
+// evaluates pred against each candidate's metadata before it is admitted to
+// the result set, so filtered-out vectors never reach distance reranking.
+// On a product-quantized shard it instead scans every code's approximate
+// distance and reranks the closest matching candidates exactly. On a
+// scalar-quantized shard, which has no precomputed distance table, it
+// decodes every matching candidate and reranks with the exact metric
+// directly.
+func (s *Shard) Search(query []float64, topN, ef int, pred filter.Predicate) ([]SearchResult, error) {
+	if s.quantizer != nil {
+		return s.searchQuantized(query, topN, pred)
+	}
+	if s.scalarQuantizer != nil {
+		return s.searchScalarQuantized(query, topN, pred)
+	}
+
+	var accept func(id string) bool
+	if pred != nil {
+		accept = func(id string) bool {
+			s.mu.RLock()
+			v, exists := s.vectors[id]
+			s.mu.RUnlock()
+			return exists && pred.Match(v.Meta)
+		}
+	}
+
+	hits, err := s.index.Search(query, topN, ef, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		v, exists := s.vectors[hit.ID]
+		if !exists {
+			// Reclaimed by a compaction that raced with this search.
+			continue
+		}
+		results = append(results, SearchResult{Vector: v, Distance: hit.Distance})
+	}
+	return results, nil
+}
+
+func (s *Shard) searchQuantized(query []float64, topN int, pred filter.Predicate) ([]SearchResult, error) {
+	table, err := s.quantizer.DistanceTable(query)
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		id   string
+		dist float64
+	}
+
+	s.mu.RLock()
+	candidates := make([]candidate, 0, len(s.codes))
+	for id, code := range s.codes {
+		if pred != nil && !pred.Match(s.vectors[id].Meta) {
+			continue
+		}
+		candidates = append(candidates, candidate{id, table.Distance(code)})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].dist < candidates[j].dist })
+
+	rerankN := topN * quantizedRerankFactor
+	if rerankN <= 0 || rerankN > len(candidates) {
+		rerankN = len(candidates)
+	}
+	candidates = candidates[:rerankN]
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]SearchResult, 0, len(candidates))
+	for _, c := range candidates {
+		v, exists := s.vectors[c.id]
+		if !exists {
+			continue
+		}
+		data := v.Data
+		if data == nil {
+			data = s.quantizer.Decode(s.codes[c.id])
+		}
+		results = append(results, SearchResult{Vector: v, Distance: s.metric.Distance(query, data)})
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Distance < results[j].Distance })
+	if len(results) > topN {
+		results = results[:topN]
+	}
+	return results, nil
+}
+
+// searchScalarQuantized decodes every matching candidate and computes the
+// exact metric directly. Scalar quantization has no distance table the way
+// product quantization does, so there is no cheaper approximate scan to
+// rerank from first.
+func (s *Shard) searchScalarQuantized(query []float64, topN int, pred filter.Predicate) ([]SearchResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]SearchResult, 0, len(s.scalarCodes))
+	for id, code := range s.scalarCodes {
+		v, exists := s.vectors[id]
+		if !exists {
+			continue
+		}
+		if pred != nil && !pred.Match(v.Meta) {
+			continue
+		}
+		data := v.Data
+		if data == nil {
+			data = s.scalarQuantizer.Decode(code)
+		}
+		results = append(results, SearchResult{Vector: v, Distance: s.metric.Distance(query, data)})
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Distance < results[j].Distance })
+	if len(results) > topN {
+		results = results[:topN]
+	}
+	return results, nil
+}
+
+// Close stops the shard's background compaction goroutine and, if the shard
+// was opened with NewShardAtPath, writes a final snapshot so the next
+// NewShardAtPath call doesn't need to re-index every vector from scratch.
+func (s *Shard) Close() error {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+	if s.path == "" {
+		return nil
+	}
+	return s.writeSnapshot(s.path)
+}
+
+func (s *Shard) runCompactionLoop() {
+	ticker := time.NewTicker(compactionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if s.index != nil {
+				s.index.CompactTombstones()
+			}
+		case <-s.stopCh:
+			return
+		}
+	}
+}
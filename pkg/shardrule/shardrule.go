@@ -0,0 +1,106 @@
+// Package shardrule lets operators place vectors across shards/nodes with a
+// small embedded JavaScript function instead of (or as an override to)
+// consistent hashing, mirroring the composite/scriptable sharding
+// approaches found in SQL sharding proxies. This enables tenant-based
+// routing, geo-partitioning, or category-based colocation without
+// recompiling Savitar.
+package shardrule
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dop251/goja"
+)
+
+// FuncName is the JavaScript function a rule must define:
+//
+//	function shardKey(id, meta) { ... return "node-0" }
+//
+// It is called with the vector's id and its metadata (as a plain JS
+// object) and must return the shard/node key to route it to.
+const FuncName = "shardKey"
+
+// Rule routes a vector to a shard/node key by evaluating a compiled
+// JavaScript function against its id and metadata.
+type Rule struct {
+	program *goja.Program
+
+	// vms pools goja.Runtimes, each with FuncName already resolved. Every
+	// Owner call takes one runtime out of the pool for its own exclusive
+	// use, so concurrent callers don't contend on a single runtime
+	// (goja.Runtime is not safe for concurrent use), and puts it back
+	// afterwards so later calls usually reuse it rather than re-running the
+	// compiled program. A runtime the pool drops is rebuilt on demand.
+	vms sync.Pool
+}
+
+// New compiles source, which must define a shardKey(id, meta) function as
+// described by FuncName, into a Rule. The program is parsed once here;
+// Owner runs the compiled bytecode in a pooled runtime rather than
+// recompiling it on every call.
+func New(source string) (*Rule, error) {
+	program, err := goja.Compile("shardrule", source, false)
+	if err != nil {
+		return nil, fmt.Errorf("shardrule: compiling rule: %w", err)
+	}
+	r := &Rule{program: program}
+	r.vms.New = func() any {
+		vm, err := r.newVM()
+		if err != nil {
+			return err
+		}
+		return vm
+	}
+
+	// Validate eagerly, so a misconfigured rule (e.g. no shardKey function)
+	// fails at load time rather than on the first Owner call.
+	v := r.vms.Get()
+	if err, ok := v.(error); ok {
+		return nil, err
+	}
+	r.vms.Put(v)
+	return r, nil
+}
+
+// vm bundles a goja.Runtime with its rule's shardKey function already
+// resolved, so Owner doesn't need to look it up on every call.
+type vm struct {
+	rt       *goja.Runtime
+	shardKey goja.Callable
+}
+
+func (r *Rule) newVM() (*vm, error) {
+	rt := goja.New()
+	if _, err := rt.RunProgram(r.program); err != nil {
+		return nil, fmt.Errorf("shardrule: running rule: %w", err)
+	}
+	fn, ok := goja.AssertFunction(rt.Get(FuncName))
+	if !ok {
+		return nil, fmt.Errorf("shardrule: rule must define a %s(id, meta) function", FuncName)
+	}
+	return &vm{rt: rt, shardKey: fn}, nil
+}
+
+// Owner evaluates the rule for a vector with the given id and metadata,
+// returning the shard/node key it routes to.
+func (r *Rule) Owner(id string, meta map[string]any) (string, error) {
+	v := r.vms.Get()
+	machine, ok := v.(*vm)
+	if !ok {
+		// Pool construction failed; New already validated the rule once,
+		// so this should be unreachable, but surface it rather than panic.
+		return "", v.(error)
+	}
+	defer r.vms.Put(machine)
+
+	result, err := machine.shardKey(goja.Undefined(), machine.rt.ToValue(id), machine.rt.ToValue(meta))
+	if err != nil {
+		return "", fmt.Errorf("shardrule: evaluating rule for %q: %w", id, err)
+	}
+	key := result.String()
+	if key == "" {
+		return "", fmt.Errorf("shardrule: rule returned an empty key for %q", id)
+	}
+	return key, nil
+}
@@ -0,0 +1,87 @@
+package shardrule
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestNewRejectsInvalidSource(t *testing.T) {
+	if _, err := New("this is not valid javascript {{{"); err == nil {
+		t.Fatal("expected an error compiling invalid source")
+	}
+}
+
+func TestNewRejectsMissingShardKeyFunction(t *testing.T) {
+	if _, err := New(`function notShardKey() { return "node-0"; }`); err == nil {
+		t.Fatal("expected an error for a rule with no shardKey function")
+	}
+}
+
+func TestOwnerRoutesByID(t *testing.T) {
+	rule, err := New(`function shardKey(id, meta) { return "node-" + (id.length % 2); }`)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	owner, err := rule.Owner("ab", nil)
+	if err != nil {
+		t.Fatalf("Owner: %v", err)
+	}
+	if owner != "node-0" {
+		t.Fatalf("Owner(\"ab\") = %q, want \"node-0\"", owner)
+	}
+}
+
+func TestOwnerRoutesByMeta(t *testing.T) {
+	rule, err := New(`function shardKey(id, meta) { return "tenant-" + meta.tenant; }`)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	owner, err := rule.Owner("v1", map[string]any{"tenant": "acme"})
+	if err != nil {
+		t.Fatalf("Owner: %v", err)
+	}
+	if owner != "tenant-acme" {
+		t.Fatalf("Owner() = %q, want \"tenant-acme\"", owner)
+	}
+}
+
+func TestOwnerRejectsEmptyKey(t *testing.T) {
+	rule, err := New(`function shardKey(id, meta) { return ""; }`)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := rule.Owner("v1", nil); err == nil {
+		t.Fatal("expected an error for a rule returning an empty key")
+	}
+}
+
+func TestOwnerPropagatesRuntimeErrors(t *testing.T) {
+	rule, err := New(`function shardKey(id, meta) { throw new Error("boom"); }`)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := rule.Owner("v1", nil); err == nil {
+		t.Fatal("expected an error from a rule that throws")
+	}
+}
+
+func TestOwnerConcurrentUse(t *testing.T) {
+	rule, err := New(`function shardKey(id, meta) { return "node-0"; }`)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := rule.Owner("v1", nil); err != nil {
+				t.Errorf("Owner: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
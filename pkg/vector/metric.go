@@ -0,0 +1,102 @@
+package vector
+
+import (
+	"fmt"
+	"math"
+)
+
+// Metric computes the distance between two equal-length vectors; smaller
+// means closer. Implementations must be safe for concurrent use and are
+// configured per collection rather than hardcoded to one similarity notion.
+type Metric interface {
+	Distance(a, b []float64) float64
+	Name() string
+}
+
+// CosineMetric measures angular distance as 1 minus cosine similarity.
+type CosineMetric struct{}
+
+// Name identifies this metric for configuration and persistence.
+func (CosineMetric) Name() string { return "cosine" }
+
+// Distance returns 1 minus the cosine similarity of a and b.
+func (CosineMetric) Distance(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 1
+	}
+	return 1 - dot/(math.Sqrt(normA)*math.Sqrt(normB))
+}
+
+// DotProductMetric measures distance as the negative inner product, so a
+// larger raw dot product (more similar) yields a smaller distance.
+type DotProductMetric struct{}
+
+// Name identifies this metric for configuration and persistence.
+func (DotProductMetric) Name() string { return "dot" }
+
+// Distance returns the negative dot product of a and b.
+func (DotProductMetric) Distance(a, b []float64) float64 {
+	var dot float64
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+	return -dot
+}
+
+// L2Metric measures ordinary Euclidean distance.
+type L2Metric struct{}
+
+// Name identifies this metric for configuration and persistence.
+func (L2Metric) Name() string { return "l2" }
+
+// Distance returns the Euclidean distance between a and b.
+func (L2Metric) Distance(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// HammingMetric measures the number of differing dimensions between two
+// binary vectors, treating each element as zero or non-zero.
+type HammingMetric struct{}
+
+// Name identifies this metric for configuration and persistence.
+func (HammingMetric) Name() string { return "hamming" }
+
+// Distance returns the count of dimensions where a and b disagree on
+// zero/non-zero.
+func (HammingMetric) Distance(a, b []float64) float64 {
+	var count float64
+	for i := range a {
+		if (a[i] != 0) != (b[i] != 0) {
+			count++
+		}
+	}
+	return count
+}
+
+// MetricByName resolves a Metric from its Name(), for configuring a
+// collection's distance function from a string (e.g. API or config input).
+func MetricByName(name string) (Metric, error) {
+	switch name {
+	case "cosine":
+		return CosineMetric{}, nil
+	case "dot":
+		return DotProductMetric{}, nil
+	case "l2":
+		return L2Metric{}, nil
+	case "hamming":
+		return HammingMetric{}, nil
+	default:
+		return nil, fmt.Errorf("vector: unknown metric %q", name)
+	}
+}